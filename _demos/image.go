@@ -0,0 +1,87 @@
+//go:build ignore
+// +build ignore
+
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// image displays an image using DrawImage
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/encoding"
+)
+
+func emitStr(s tcell.Screen, x, y int, style tcell.Style, str string) {
+	for _, c := range str {
+		s.SetContent(x, y, c, nil, style)
+		x++
+	}
+}
+
+func display(s tcell.Screen, img image.Image) {
+	w, h := s.Size()
+	s.Clear()
+	emitStr(s, 1, h-1, tcell.StyleDefault, "Press ESC to exit.")
+	if !s.DrawImage(w/4, 1, w/2, h-3, img) {
+		emitStr(s, 1, 1, tcell.StyleDefault, "This terminal cannot display images.")
+	}
+	s.Show()
+}
+
+func main() {
+	encoding.Register()
+
+	f, err := os.Open("./logos/tcell.png")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "couldn't load image. try running from the root directory")
+		os.Exit(1)
+	}
+	img, err := png.Decode(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	s, e := tcell.NewScreen()
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	if e := s.Init(); e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	s.ProbeCapabilities(time.Second)
+	display(s, img)
+
+	for {
+		switch ev := s.PollEvent().(type) {
+		case *tcell.EventResize:
+			display(s, img)
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyEscape {
+				s.Fini()
+				os.Exit(0)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// Capabilities describes features of the terminal.  Unless the terminal
+// has been probed (see Screen.ProbeCapabilities), these are based on what
+// is recorded in the terminal database, or what the screen otherwise
+// believes to be true, and may be inaccurate.
+type Capabilities struct {
+	Sixel          bool   // Sixel graphics
+	TrueColor      bool   // 24-bit color
+	SyncOutput     bool   // synchronized output (mode 2026)
+	Focus          bool   // focus reporting
+	BracketedPaste bool   // bracketed paste
+	KittyKeyboard  bool   // kitty keyboard protocol
+	KittyGraphics  bool   // kitty graphics protocol
+	Graphemes      bool   // grapheme cluster mode (mode 2027)
+	Name           string // terminal program name, if it said (else empty)
+	Version        string // terminal program version, if known
+	Probed         bool   // true if the terminal replied to a probe
+}
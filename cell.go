@@ -19,6 +19,7 @@ import (
 	"reflect"
 
 	runewidth "github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
 )
 
 type cell struct {
@@ -39,9 +40,10 @@ type cell struct {
 //
 // CellBuffer is not thread safe.
 type CellBuffer struct {
-	w     int
-	h     int
-	cells []cell
+	w      int
+	h      int
+	cells  []cell
+	widths *WidthPolicy
 }
 
 // SetContent sets the contents (primary rune, combining runes,
@@ -54,20 +56,25 @@ func (cb *CellBuffer) SetContent(x int, y int,
 	if x >= 0 && y >= 0 && x < cb.w && y < cb.h {
 		c := &cb.cells[(y*cb.w)+x]
 
+		changed := mainc != c.currMain || len(combc) != len(c.currComb) ||
+			(len(combc) > 0 && !reflect.DeepEqual(combc, c.currComb))
+
 		// Wide characters: we want to mark the "wide" cells
 		// dirty as well as the base cell, to make sure we consider
 		// both cells as dirty together.  We only need to do this
-		// if we're changing content
-		if (c.width > 0) && (mainc != c.currMain || len(combc) != len(c.currComb) || (len(combc) > 0 && !reflect.DeepEqual(combc, c.currComb))) {
-			for i := 0; i < c.width; i++ {
+		// if we're changing content.  The base cell itself will be
+		// seen as dirty anyway, and we want to keep what was last
+		// drawn there, as that lets us scroll it.
+		if c.width > 1 && changed {
+			for i := 1; i < c.width; i++ {
 				cb.SetDirty(x+i, y, true)
 			}
 		}
 
 		c.currComb = append([]rune{}, combc...)
 
-		if c.currMain != mainc {
-			c.width = runewidth.RuneWidth(mainc)
+		if changed {
+			c.width = cb.policy().clusterWidth(mainc, combc)
 		}
 		c.currMain = mainc
 		if style.fg == ColorNone {
@@ -80,10 +87,58 @@ func (cb *CellBuffer) SetContent(x int, y int,
 	}
 }
 
+// Put sets the contents of a cell to the first extended grapheme cluster
+// (as defined by Unicode UAX #29) of the string, such as a letter with its
+// accents, an emoji ZWJ sequence, or a flag.  It returns the rest of the
+// string, and the width of the cluster in cells, so that the caller can
+// continue with the next cell.  (The width is returned even if the location
+// is out of range.)  The style is handled as for SetContent.
+func (cb *CellBuffer) Put(x, y int, str string, style Style) (string, int) {
+	cluster, rest, _, _ := uniseg.FirstGraphemeClusterInString(str, -1)
+	if cluster == "" {
+		return "", 0
+	}
+	runes := []rune(cluster)
+	cb.SetContent(x, y, runes[0], runes[1:], style)
+	return rest, max(cb.policy().clusterWidth(runes[0], runes[1:]), 1)
+}
+
+// SetWidthPolicy sets the policy used to determine the width of the
+// content of cells.  The widths of the current content are updated,
+// and all cells are invalidated.
+func (cb *CellBuffer) SetWidthPolicy(wp WidthPolicy) {
+	wp.Overrides = append([]WidthRange{}, wp.Overrides...)
+	cb.widths = &wp
+	for i := range cb.cells {
+		c := &cb.cells[i]
+		if c.currMain != 0 {
+			c.width = wp.clusterWidth(c.currMain, c.currComb)
+		}
+		c.lastMain = rune(0)
+	}
+}
+
+// GetWidthPolicy returns the policy used to determine the width of the
+// content of cells.
+func (cb *CellBuffer) GetWidthPolicy() WidthPolicy {
+	wp := cb.policy()
+	wp.Overrides = append([]WidthRange{}, wp.Overrides...)
+	return wp
+}
+
+func (cb *CellBuffer) policy() WidthPolicy {
+	if cb.widths == nil {
+		return DefaultWidthPolicy()
+	}
+	return *cb.widths
+}
+
 // GetContent returns the contents of a character cell, including the
 // primary rune, any combining character runes (which will usually be
 // nil), the style, and the display width in cells.  (The width can be
-// either 1, normally, or 2 for East Asian full-width characters.)
+// either 1, normally, or 2 for East Asian full-width characters and emoji.)
+// The primary rune and combining runes together are the grapheme cluster
+// stored in the cell, exactly as given to SetContent or Put.
 func (cb *CellBuffer) GetContent(x, y int) (rune, []rune, Style, int) {
 	var mainc rune
 	var combc []rune
@@ -177,6 +232,14 @@ func (cb *CellBuffer) LockCell(x, y int) {
 	c.lock = true
 }
 
+// locked reports whether the cell is locked.
+func (cb *CellBuffer) locked(x, y int) bool {
+	if x < 0 || y < 0 || x >= cb.w || y >= cb.h {
+		return false
+	}
+	return cb.cells[(y*cb.w)+x].lock
+}
+
 // UnlockCell removes a lock from the cell and marks it as dirty
 func (cb *CellBuffer) UnlockCell(x, y int) {
 	if x < 0 || y < 0 {
@@ -237,6 +300,213 @@ func (cb *CellBuffer) Fill(r rune, style Style) {
 	}
 }
 
+// rowHash computes hashes of each row, for both the current content and
+// the content last drawn.  A row whose last drawn content is unknown
+// (invalidated, or locked) is reported as not valid.
+func (cb *CellBuffer) rowHash() (curr []uint64, last []uint64, valid []bool) {
+	curr = make([]uint64, cb.h)
+	last = make([]uint64, cb.h)
+	valid = make([]bool, cb.h)
+	wp := cb.policy()
+	for y := 0; y < cb.h; y++ {
+		row := cb.cells[y*cb.w : (y+1)*cb.w]
+		ch, lh := uint64(fnvOffset), uint64(fnvOffset)
+		ok := true
+		cskip, lskip := 0, 0
+		for x := range row {
+			c := &row[x]
+			if c.lock {
+				ok = false
+			}
+			// cells covered by a wide character to their left are
+			// never drawn themselves, so they don't count
+			if cskip > 0 {
+				cskip--
+			} else {
+				mainc := c.currMain
+				if mainc == rune(0) {
+					mainc = ' '
+				}
+				ch = hashCell(ch, mainc, c.currComb, c.currStyle)
+				cskip = c.width - 1
+			}
+			if lskip > 0 {
+				lskip--
+			} else if c.lastMain == rune(0) {
+				ok = false
+			} else {
+				lh = hashCell(lh, c.lastMain, c.lastComb, c.lastStyle)
+				lskip = wp.clusterWidth(c.lastMain, c.lastComb) - 1
+			}
+		}
+		curr[y], last[y], valid[y] = ch, lh, ok
+	}
+	return curr, last, valid
+}
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+func hashCell(h uint64, mainc rune, combc []rune, style Style) uint64 {
+	mix := func(v uint64) {
+		h ^= v
+		h *= fnvPrime
+	}
+	mix(uint64(mainc))
+	for _, r := range combc {
+		mix(uint64(r))
+	}
+	mix(uint64(style.fg))
+	mix(uint64(style.bg))
+	mix(uint64(style.ulColor))
+	mix(uint64(style.ulStyle))
+	mix(uint64(style.attrs))
+	for i := 0; i < len(style.url); i++ {
+		mix(uint64(style.url[i]))
+	}
+	return h
+}
+
+// sameRow reports whether the current content of row y is identical
+// to the content last drawn at row ly.
+func (cb *CellBuffer) sameRow(y, ly int) bool {
+	for x := 0; x < cb.w; x++ {
+		c := &cb.cells[y*cb.w+x]
+		l := &cb.cells[ly*cb.w+x]
+		mainc := c.currMain
+		if mainc == rune(0) {
+			mainc = ' '
+		}
+		if mainc != l.lastMain || c.currStyle != l.lastStyle || len(c.currComb) != len(l.lastComb) {
+			return false
+		}
+		for i := range c.currComb {
+			if c.currComb[i] != l.lastComb[i] {
+				return false
+			}
+		}
+		if c.width > 1 {
+			x += c.width - 1
+		}
+	}
+	return true
+}
+
+// findScroll looks for a run of rows whose new content was previously
+// drawn n rows further down (n > 0), or up (n < 0).  If moving those rows
+// on the terminal (by scrolling the region top through bot) saves redrawing
+// any rows, the region and the distance are returned.  Otherwise n is zero.
+func (cb *CellBuffer) findScroll() (top, bot, n int) {
+	curr, last, valid := cb.rowHash()
+	dirty := 0
+	for y := range curr {
+		if !valid[y] || curr[y] != last[y] {
+			dirty++
+		}
+	}
+	if dirty < 2 {
+		return 0, 0, 0
+	}
+
+	best := 0
+	for d := 1; d < cb.h; d++ {
+		for _, s := range []int{d, -d} {
+			start := -1
+			for y := 0; y <= cb.h; y++ {
+				ly := y + s
+				if y < cb.h && ly >= 0 && ly < cb.h && valid[ly] && curr[y] == last[ly] {
+					if start < 0 {
+						start = y
+					}
+					continue
+				}
+				if start < 0 {
+					continue
+				}
+				a, b := start, y-1
+				start = -1
+
+				// the scroll region covers the source rows as well as
+				// the destination, and the rows it exposes must be redrawn.
+				rt, rb := a, b+s
+				if s < 0 {
+					rt, rb = a+s, b
+				}
+				score := 0
+				for r := rt; r <= rb; r++ {
+					moved := r >= a && r <= b
+					clean := valid[r] && curr[r] == last[r]
+					if moved && !clean {
+						score++
+					} else if !moved && clean {
+						score--
+					}
+				}
+				if score > best && cb.canScroll(rt, rb) {
+					best, top, bot, n = score, rt, rb, s
+				}
+			}
+		}
+	}
+	if n == 0 {
+		return 0, 0, 0
+	}
+	// guard against hash collisions
+	from, to := top, bot-n
+	if n < 0 {
+		from, to = top-n, bot
+	}
+	for y := from; y <= to; y++ {
+		if !cb.sameRow(y, y+n) {
+			return 0, 0, 0
+		}
+	}
+	return top, bot, n
+}
+
+// canScroll reports whether the rows top through bot may be scrolled,
+// which is not the case if any cell in them is locked.
+func (cb *CellBuffer) canScroll(top, bot int) bool {
+	for _, c := range cb.cells[top*cb.w : (bot+1)*cb.w] {
+		if c.lock {
+			return false
+		}
+	}
+	return true
+}
+
+// scrollLast updates the record of what was last drawn to reflect
+// the rows top through bot having been scrolled up by n rows (or down,
+// if n is negative) on the terminal.  The rows exposed by the scroll
+// are marked dirty.
+func (cb *CellBuffer) scrollLast(top, bot, n int) {
+	move := func(dy, sy int) {
+		for x := 0; x < cb.w; x++ {
+			d := &cb.cells[dy*cb.w+x]
+			s := &cb.cells[sy*cb.w+x]
+			d.lastMain, d.lastComb, d.lastStyle = s.lastMain, s.lastComb, s.lastStyle
+		}
+	}
+	if n > 0 {
+		for y := top; y <= bot-n; y++ {
+			move(y, y+n)
+		}
+		top = bot - n + 1
+	} else {
+		for y := bot; y >= top-n; y-- {
+			move(y, y+n)
+		}
+		bot = top - n - 1
+	}
+	for y := top; y <= bot; y++ {
+		for x := 0; x < cb.w; x++ {
+			cb.cells[y*cb.w+x].lastMain = rune(0)
+		}
+	}
+}
+
 var runeConfig *runewidth.Condition
 
 func init() {
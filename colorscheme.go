@@ -0,0 +1,76 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"time"
+)
+
+// ColorScheme describes whether the terminal uses dark or light colors.
+type ColorScheme int
+
+const (
+	ColorSchemeUnknown = ColorScheme(iota)
+	ColorSchemeDark
+	ColorSchemeLight
+)
+
+// EventColorScheme reports the default foreground and background colors
+// of the terminal, and whether that makes for a dark or a light scheme.
+// It is sent in reply to GetColorScheme, and when the user changes the
+// terminal's theme, if enabled with EnableColorScheme.
+type EventColorScheme struct {
+	t      time.Time
+	scheme ColorScheme
+	fg     Color
+	bg     Color
+}
+
+// NewEventColorScheme returns a new EventColorScheme.  If the scheme is
+// ColorSchemeUnknown, it is derived from the background color.
+func NewEventColorScheme(scheme ColorScheme, fg, bg Color) *EventColorScheme {
+	if scheme == ColorSchemeUnknown && bg.Valid() {
+		r, g, b := bg.RGB()
+		// perceived brightness, per ITU-R BT.601
+		if r*299+g*587+b*114 < 128*1000 {
+			scheme = ColorSchemeDark
+		} else {
+			scheme = ColorSchemeLight
+		}
+	}
+	return &EventColorScheme{t: time.Now(), scheme: scheme, fg: fg, bg: bg}
+}
+
+// When returns the time when this event was created.
+func (ev *EventColorScheme) When() time.Time {
+	return ev.t
+}
+
+// Scheme returns whether the colors are dark or light.
+func (ev *EventColorScheme) Scheme() ColorScheme {
+	return ev.scheme
+}
+
+// Foreground returns the default foreground color, or ColorDefault
+// if the terminal did not report it.
+func (ev *EventColorScheme) Foreground() Color {
+	return ev.fg
+}
+
+// Background returns the default background color, or ColorDefault
+// if the terminal did not report it.
+func (ev *EventColorScheme) Background() Color {
+	return ev.bg
+}
@@ -22,3 +22,7 @@ package tcell
 func NewConsoleScreen() (Screen, error) {
 	return nil, ErrNoScreen
 }
+
+func newConsoleScreen([]ScreenOption) (Screen, error) {
+	return nil, ErrNoScreen
+}
@@ -18,12 +18,15 @@
 package tcell
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"image"
 	"os"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 	"unicode/utf16"
 	"unsafe"
 )
@@ -39,9 +42,11 @@ type cScreen struct {
 	style      Style
 	fini       bool
 	vten       bool
+	clicks     mouseTracker
 	truecolor  bool
 	running    bool
 	disableAlt bool // disable the alternate screen
+	opts       screenOptions
 	title      string
 
 	w int
@@ -55,12 +60,13 @@ type cScreen struct {
 	oomode      uint32
 	cells       CellBuffer
 	focusEnable bool
+	kbdFlags    KeyboardFlags
 
-	mouseEnabled bool
-	wg           sync.WaitGroup
-	eventQ       chan Event
-	stopQ        chan struct{}
-	finiOnce     sync.Once
+	mouseFlags MouseFlags
+	wg         sync.WaitGroup
+	eventQ     *eventQueue
+	stopQ      chan struct{}
+	finiOnce   sync.Once
 
 	sync.Mutex
 }
@@ -196,11 +202,22 @@ var vtCursorStyles = map[CursorStyle]string{
 // with the current process.  The Screen makes use of the Windows Console
 // API to display content and read events.
 func NewConsoleScreen() (Screen, error) {
-	return &baseScreen{screenImpl: &cScreen{}}, nil
+	return newConsoleScreen(nil)
+}
+
+func newConsoleScreen(opts []ScreenOption) (Screen, error) {
+	o := newScreenOptions(opts)
+	if o.inline > 0 {
+		// the console has no way to occupy just some lines
+		return nil, ErrNotSupported
+	}
+	s := &cScreen{eventQ: newEventQueue(o.queueSize), opts: o}
+	s.mouseFlags = o.mouse
+	s.focusEnable = o.focus
+	return &baseScreen{screenImpl: s}, nil
 }
 
 func (s *cScreen) Init() error {
-	s.eventQ = make(chan Event, 10)
 	s.quit = make(chan struct{})
 	s.scandone = make(chan struct{})
 	in, e := syscall.Open("CONIN$", syscall.O_RDWR, 0)
@@ -234,6 +251,11 @@ func (s *cScreen) Init() error {
 		s.truecolor = true
 		tryVt = true
 	}
+	if s.opts.trueColor != nil {
+		s.truecolor = *s.opts.trueColor
+		tryVt = tryVt || s.truecolor
+	}
+	s.disableAlt = !s.opts.useAltScreen()
 
 	s.Lock()
 
@@ -258,12 +280,6 @@ func (s *cScreen) Init() error {
 	case "enable":
 		tryVt = true
 	}
-	switch os.Getenv("TCELL_ALTSCREEN") {
-	case "enable":
-		s.disableAlt = false // also the default
-	case "disable":
-		s.disableAlt = true
-	}
 	if tryVt {
 		s.setOutMode(modeVtOutput | modeNoAutoNL | modeCookedOut | modeUnderline)
 		var om uint32
@@ -288,16 +304,27 @@ func (s *cScreen) CharacterSet() string {
 	return "UTF-16LE"
 }
 
-func (s *cScreen) EnableMouse(...MouseFlags) {
+func (s *cScreen) EnableMouse(flags ...MouseFlags) {
+	var f MouseFlags
+	for _, flag := range flags {
+		f |= flag
+	}
+	if len(flags) == 0 {
+		f = MouseMotionEvents | MouseDragEvents | MouseButtonEvents
+	}
 	s.Lock()
-	s.mouseEnabled = true
-	s.enableMouse(true)
+	s.mouseFlags = f
+	s.enableMouse(f != 0)
 	s.Unlock()
 }
 
+func (s *cScreen) SetMouseClickInterval(interval time.Duration) {
+	s.clicks.setInterval(interval)
+}
+
 func (s *cScreen) DisableMouse() {
 	s.Lock()
-	s.mouseEnabled = false
+	s.mouseFlags = 0
 	s.enableMouse(false)
 	s.Unlock()
 }
@@ -316,6 +343,8 @@ func (s *cScreen) EnablePaste() {}
 
 func (s *cScreen) DisablePaste() {}
 
+func (s *cScreen) SetPasteLimit(int) {}
+
 func (s *cScreen) EnableFocus() {
 	s.Lock()
 	s.focusEnable = true
@@ -328,6 +357,12 @@ func (s *cScreen) DisableFocus() {
 	s.Unlock()
 }
 
+func (s *cScreen) SetKeyboardFlags(flags KeyboardFlags) {
+	s.Lock()
+	s.kbdFlags = flags
+	s.Unlock()
+}
+
 func (s *cScreen) Fini() {
 	s.finiOnce.Do(func() {
 		close(s.quit)
@@ -387,7 +422,7 @@ func (s *cScreen) engage() error {
 	}
 	s.running = true
 	s.cancelflag = syscall.Handle(cf)
-	s.enableMouse(s.mouseEnabled)
+	s.enableMouse(s.mouseFlags != 0)
 
 	if s.vten {
 		s.setOutMode(modeVtOutput | modeNoAutoNL | modeCookedOut | modeUnderline)
@@ -534,7 +569,7 @@ const (
 	mouseHWheeled uint32 = 0x8
 	mouseVWheeled uint32 = 0x4
 	// mouseDoubleClick uint32 = 0x2
-	// mouseMoved       uint32 = 0x1
+	mouseMoved uint32 = 0x1
 )
 
 type resizeRecord struct {
@@ -736,10 +771,7 @@ func mrec2btns(mbtns, flags uint32) ButtonMask {
 }
 
 func (s *cScreen) postEvent(ev Event) {
-	select {
-	case s.eventQ <- ev:
-	case <-s.quit:
-	}
+	_ = s.eventQ.post(ev, s.quit)
 }
 
 func (s *cScreen) getConsoleInput() error {
@@ -783,8 +815,18 @@ func (s *cScreen) getConsoleInput() error {
 			krec.ch = getu16(rec.data[10:])
 			krec.mod = getu32(rec.data[12:])
 
-			if krec.isdown == 0 || krec.repeat < 1 {
-				// it's a key release event, ignore it
+			et := KeyEventPress
+			if krec.isdown == 0 {
+				// it's a key release event, ignore it unless asked for
+				s.Lock()
+				flags := s.kbdFlags
+				s.Unlock()
+				if flags&KeyReleaseEvents == 0 {
+					return nil
+				}
+				et = KeyEventRelease
+				krec.repeat = 1
+			} else if krec.repeat < 1 {
 				return nil
 			}
 			if krec.ch != 0 {
@@ -792,9 +834,9 @@ func (s *cScreen) getConsoleInput() error {
 				for krec.repeat > 0 {
 					// convert shift+tab to backtab
 					if mod2mask(krec.mod) == ModShift && krec.ch == vkTab {
-						s.postEvent(NewEventKey(KeyBacktab, 0, ModNone))
+						s.postEvent(newEventKeyType(KeyBacktab, 0, ModNone, et))
 					} else {
-						s.postEvent(NewEventKey(KeyRune, rune(krec.ch), mod2mask(krec.mod)))
+						s.postEvent(newEventKeyType(KeyRune, rune(krec.ch), mod2mask(krec.mod), et))
 					}
 					krec.repeat--
 				}
@@ -806,7 +848,7 @@ func (s *cScreen) getConsoleInput() error {
 				return nil
 			}
 			for krec.repeat > 0 {
-				s.postEvent(NewEventKey(key, rune(krec.ch), mod2mask(krec.mod)))
+				s.postEvent(newEventKeyType(key, rune(krec.ch), mod2mask(krec.mod), et))
 				krec.repeat--
 			}
 
@@ -818,8 +860,24 @@ func (s *cScreen) getConsoleInput() error {
 			mrec.mod = getu32(rec.data[8:])
 			mrec.flags = getu32(rec.data[12:])
 			btns := mrec2btns(mrec.btns, mrec.flags)
-			// we ignore double click, events are delivered normally
-			s.postEvent(NewEventMouse(int(mrec.x), int(mrec.y), btns, mod2mask(mrec.mod)))
+			// the console always reports motion, so we drop what was
+			// not asked for, as terminals would not have reported it
+			if mrec.flags&mouseMoved != 0 {
+				s.Lock()
+				flags := s.mouseFlags
+				s.Unlock()
+				if btns == ButtonNone && flags&MouseMotionEvents == 0 {
+					break
+				}
+				if flags&(MouseMotionEvents|MouseDragEvents) == 0 {
+					break
+				}
+			}
+			// we ignore double click, events are delivered normally,
+			// and we count clicks ourselves the same way everywhere
+			ev := NewEventMouse(int(mrec.x), int(mrec.y), btns, mod2mask(mrec.mod))
+			s.clicks.track(ev)
+			s.postEvent(ev)
 
 		case resizeEvent:
 			var rrec resizeRecord
@@ -1213,10 +1271,7 @@ func (s *cScreen) resize() {
 		uintptr(s.out),
 		uintptr(1),
 		uintptr(unsafe.Pointer(&r)))
-	select {
-	case s.eventQ <- NewEventResize(w, h):
-	default:
-	}
+	_ = s.eventQ.post(NewEventResize(w, h), nil)
 }
 
 func (s *cScreen) clearScreen(style Style, vtEnable bool) {
@@ -1306,6 +1361,9 @@ func (s *cScreen) SetTitle(title string) {
 	s.Unlock()
 }
 
+// The console has no means to change the mouse pointer.
+func (s *cScreen) SetMouseShape(MouseShape) {}
+
 // No fallback rune support, since we have Unicode.  Yay!
 
 func (s *cScreen) RegisterRuneFallback(_ rune, _ string) {
@@ -1331,6 +1389,77 @@ func (s *cScreen) SetClipboard(_ []byte) {
 func (s *cScreen) GetClipboard() {
 }
 
+func (s *cScreen) GetClipboardContext(context.Context) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *cScreen) PrintAbove(...string) {
+}
+
+func (s *cScreen) DrawImage(int, int, int, int, image.Image) bool {
+	return false
+}
+
+func (s *cScreen) LoadImage(image.Image) Image {
+	return nil
+}
+
+func (s *cScreen) GetColorScheme() {
+}
+
+func (s *cScreen) EnableColorScheme() {
+}
+
+func (s *cScreen) DisableColorScheme() {
+}
+
+func (s *cScreen) SetPaletteColor(int, Color) {
+}
+
+func (s *cScreen) ResetPalette() {
+}
+
+func (s *cScreen) QueryPaletteColor(int) {
+}
+
+func (s *cScreen) QueryCursorPosition() {
+	x, y, _ := s.GetCursorPosition(0)
+	s.postEvent(NewEventCursorPosition(x, y))
+}
+
+func (s *cScreen) GetCursorPosition(time.Duration) (int, int, bool) {
+	info := consoleInfo{}
+	s.getConsoleInfo(&info)
+	return int(info.pos.x - info.win.left), int(info.pos.y - info.win.top), true
+}
+
+func (s *cScreen) Query(context.Context, string, ReplyMatcher) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *cScreen) PostQuery(string, ReplyMatcher) {
+}
+
+func (s *cScreen) Capabilities() Capabilities {
+	s.Lock()
+	defer s.Unlock()
+	return Capabilities{
+		TrueColor: s.truecolor,
+		Focus:     true,
+		Name:      "Windows Console",
+	}
+}
+
+func (s *cScreen) ProbeCapabilities(time.Duration) Capabilities {
+	return s.Capabilities()
+}
+
+func (s *cScreen) ProbeWidths(time.Duration) WidthPolicy {
+	s.Lock()
+	defer s.Unlock()
+	return s.cells.GetWidthPolicy()
+}
+
 func (s *cScreen) Resize(int, int, int, int) {}
 
 func (s *cScreen) HasKey(k Key) bool {
@@ -1402,7 +1531,7 @@ func (s *cScreen) GetCells() *CellBuffer {
 	return &s.cells
 }
 
-func (s *cScreen) EventQ() chan Event {
+func (s *cScreen) EventQ() *eventQueue {
 	return s.eventQ
 }
 
@@ -0,0 +1,43 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"time"
+)
+
+// EventCursorPosition reports the position of the cursor on the terminal.
+// It is sent in reply to QueryCursorPosition.
+type EventCursorPosition struct {
+	t time.Time
+	x int
+	y int
+}
+
+// NewEventCursorPosition returns a new EventCursorPosition.
+func NewEventCursorPosition(x, y int) *EventCursorPosition {
+	return &EventCursorPosition{t: time.Now(), x: x, y: y}
+}
+
+// When returns the time when this event was created.
+func (ev *EventCursorPosition) When() time.Time {
+	return ev.t
+}
+
+// Position returns the column and row of the cursor, counting from zero
+// at the top left of the terminal.
+func (ev *EventCursorPosition) Position() (int, int) {
+	return ev.x, ev.y
+}
@@ -0,0 +1,365 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package tcell
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2/terminfo"
+)
+
+// MouseEncoding is a way of reporting mouse events.  The values are the
+// numbers of the terminal modes that select them.
+type MouseEncoding int
+
+const (
+	MouseEncodingX10   = MouseEncoding(0)    // Legacy X11, up to 223 rows and columns
+	MouseEncodingUTF8  = MouseEncoding(1005) // X11, with UTF-8 coordinates
+	MouseEncodingSGR   = MouseEncoding(1006) // SGR, the most common today
+	MouseEncodingURXVT = MouseEncoding(1015) // Decimal, as used by urxvt
+)
+
+// Encoder converts events back into the bytes that a terminal would send
+// for them.  This is the reverse of what a Screen does with its input, and
+// is useful for passing input on to another program, such as one running
+// in a pseudo-terminal, or in a remote session.  Key sequences are taken
+// from the terminfo description, and text is encoded as UTF-8.
+//
+// The program receiving the input chooses how the mouse is reported,
+// whether keys use the kitty keyboard protocol, and whether pastes are
+// bracketed, so the Encoder has to be told when those change.  The Encoder
+// keeps track of which mouse buttons are held, so events should be encoded
+// in the order they happened.
+type Encoder struct {
+	keys    map[tKeyCode]string
+	mouse   MouseEncoding
+	kitty   bool
+	paste   bool
+	buttons ButtonMask
+	sync.Mutex
+}
+
+// NewEncoder returns an Encoder for the terminal described.  Initially,
+// mouse events use the SGR encoding, keys are sent without the kitty
+// keyboard protocol, and pastes are bracketed.
+func NewEncoder(ti *terminfo.Terminfo) *Encoder {
+	// a screen that is never started, just to learn the key sequences
+	t := &tScreen{ti: ti}
+	t.keyexist = make(map[Key]bool)
+	t.keycodes = make(map[string]*tKeyCode)
+	t.keyseqs = make(map[tKeyCode]string)
+	t.prepareKeys()
+	return &Encoder{keys: t.keyseqs, mouse: MouseEncodingSGR, paste: true}
+}
+
+// SetMouseEncoding sets the encoding used for mouse events.
+func (e *Encoder) SetMouseEncoding(enc MouseEncoding) {
+	e.Lock()
+	e.mouse = enc
+	e.Unlock()
+}
+
+// SetKittyKeyboard determines whether keys are sent using the kitty keyboard
+// protocol.  Only then are key release and repeat events encoded; otherwise
+// releases are dropped, and repeats are sent as presses.
+func (e *Encoder) SetKittyKeyboard(on bool) {
+	e.Lock()
+	e.kitty = on
+	e.Unlock()
+}
+
+// SetBracketedPaste determines whether pastes are bracketed.  If not, then
+// only the text of a paste is sent.
+func (e *Encoder) SetBracketedPaste(on bool) {
+	e.Lock()
+	e.paste = on
+	e.Unlock()
+}
+
+// Encode returns the bytes for the event, or nil if the event cannot be
+// represented (or is not input, like a resize).  Paste events for the start
+// and end of a paste send the paste markers, and one that holds the entire
+// paste (see SetPasteLimit) sends the text as well.
+func (e *Encoder) Encode(ev Event) []byte {
+	e.Lock()
+	defer e.Unlock()
+	switch ev := ev.(type) {
+	case *EventKey:
+		return e.encodeKey(ev)
+	case *EventMouse:
+		return e.encodeMouse(ev)
+	case *EventPaste:
+		return e.encodePaste(ev)
+	case *EventFocus:
+		if ev.Focused {
+			return []byte("\x1b[I")
+		}
+		return []byte("\x1b[O")
+	}
+	return nil
+}
+
+func (e *Encoder) encodeKey(ev *EventKey) []byte {
+	key, ch, mod := ev.Key(), ev.Rune(), ev.Modifiers()
+	if e.kitty {
+		if b := e.kittyKey(key, ch, mod, ev.EventType()); b != nil {
+			return b
+		}
+	}
+	if ev.EventType() == KeyEventRelease {
+		return nil
+	}
+
+	var b []byte
+	switch {
+	case key == KeyRune:
+		if mod&ModAlt != 0 {
+			b = append(b, '\x1b')
+		}
+		if mod&ModCtrl != 0 {
+			if k := ctrlKey(unicode.ToLower(ch)); k >= 0 {
+				return append(b, byte(k))
+			}
+		}
+		return utf8.AppendRune(b, ch)
+	case key < ' ' || key == KeyDEL:
+		if mod&ModAlt != 0 {
+			b = append(b, '\x1b')
+		}
+		return append(b, byte(key))
+	}
+	if s, ok := e.keys[tKeyCode{key: key, mod: mod}]; ok {
+		return []byte(s)
+	}
+	if mod&ModAlt != 0 {
+		// the ESC prefix is understood to mean Alt
+		if s, ok := e.keys[tKeyCode{key: key, mod: mod &^ ModAlt}]; ok {
+			return []byte("\x1b" + s)
+		}
+	}
+	return nil
+}
+
+// kittyKey encodes a key using the kitty keyboard protocol.  It returns
+// nil for plain text, which is sent as it is.
+func (e *Encoder) kittyKey(key Key, ch rune, mod ModMask, et KeyEventType) []byte {
+	code, shifted := 0, 0
+	final := byte('u')
+	switch {
+	case key == KeyRune:
+		if mod == ModNone && et == KeyEventPress {
+			return nil
+		}
+		code = int(ch)
+		if lower := unicode.ToLower(ch); lower != ch {
+			// keys are identified by their unshifted code, with the
+			// shifted one given as an alternate
+			code, shifted = int(lower), int(ch)
+			mod |= ModShift
+		}
+	case key == KeyBacktab:
+		code = int(KeyTab)
+		mod |= ModShift
+	case key == KeyTab || key == KeyEnter || key == KeyEsc:
+		code = int(key)
+	case key == KeyBackspace || key == KeyBackspace2:
+		code = 127
+	case key < ' ':
+		code = int(ctrlRune(key))
+		mod |= ModCtrl
+	default:
+		if code = kittyLetterCode(key); code != 0 {
+			final = byte(code)
+			code = 1
+		} else if code = kittyTildeCode(key); code != 0 {
+			final = '~'
+		} else if code = kittyFuncCode(key); code == 0 {
+			return nil
+		}
+	}
+
+	m := 1
+	for i, bit := range []ModMask{ModShift, ModAlt, ModCtrl, ModSuper, ModHyper, ModMeta, ModCapsLock, ModNumLock} {
+		if mod&bit != 0 {
+			m += 1 << i
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString("\x1b[")
+	if final == 'u' || final == '~' || m != 1 || et != KeyEventPress {
+		sb.WriteString(strconv.Itoa(code))
+	}
+	if shifted != 0 {
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(shifted))
+	}
+	if m != 1 || et != KeyEventPress {
+		sb.WriteByte(';')
+		sb.WriteString(strconv.Itoa(m))
+	}
+	if et != KeyEventPress {
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(int(et) + 1))
+	}
+	sb.WriteByte(final)
+	return []byte(sb.String())
+}
+
+// ctrlRune is the reverse of ctrlKey.
+func ctrlRune(key Key) rune {
+	switch {
+	case key == KeyCtrlSpace:
+		return ' '
+	case key <= KeyCtrlZ:
+		return rune(key-KeyCtrlA) + 'a'
+	}
+	return rune(key-KeyCtrlLeftSq) + '['
+}
+
+func kittyLetterCode(key Key) int {
+	for c, k := range kittyLetterKeys {
+		if k == key {
+			return int(c)
+		}
+	}
+	return 0
+}
+
+func kittyTildeCode(key Key) int {
+	for c, k := range kittyTildeKeys {
+		if k == key {
+			return c
+		}
+	}
+	return 0
+}
+
+func kittyFuncCode(key Key) int {
+	for c, k := range kittyFuncKeys {
+		if k.key == key {
+			return c
+		}
+	}
+	return 0
+}
+
+func (e *Encoder) encodeMouse(ev *EventMouse) []byte {
+	const buttons = Button1 | Button2 | Button3
+	const wheels = WheelUp | WheelDown | WheelLeft | WheelRight
+	btn := ev.Buttons()
+	x, y := ev.Position()
+	x, y = max(x, 0), max(y, 0)
+
+	// Only one button is reported at a time.  Note that the right button
+	// is Button2 in X11, but we call it Button3.
+	codeOf := func(btn ButtonMask) int {
+		switch {
+		case btn&WheelUp != 0:
+			return 64
+		case btn&WheelDown != 0:
+			return 65
+		case btn&WheelLeft != 0:
+			return 66
+		case btn&WheelRight != 0:
+			return 67
+		case btn&Button1 != 0:
+			return 0
+		case btn&Button3 != 0:
+			return 1
+		case btn&Button2 != 0:
+			return 2
+		}
+		return 3
+	}
+	code := codeOf(btn)
+	release := false
+	if btn&wheels == 0 {
+		held := e.buttons
+		e.buttons = btn & buttons
+		switch {
+		case btn&buttons == 0 && held != 0:
+			release = true
+			if e.mouse == MouseEncodingSGR {
+				// SGR reports which button was released
+				code = codeOf(held)
+			}
+		case btn&buttons == held:
+			code += 32 // motion
+		}
+	}
+	if ev.Modifiers()&ModShift != 0 {
+		code |= 4
+	}
+	if ev.Modifiers()&ModAlt != 0 {
+		code |= 8
+	}
+	if ev.Modifiers()&ModCtrl != 0 {
+		code |= 16
+	}
+
+	switch e.mouse {
+	case MouseEncodingSGR:
+		final := "M"
+		if release {
+			final = "m"
+		}
+		return []byte("\x1b[<" + strconv.Itoa(code) + ";" + strconv.Itoa(x+1) + ";" + strconv.Itoa(y+1) + final)
+	case MouseEncodingURXVT:
+		return []byte("\x1b[" + strconv.Itoa(code+32) + ";" + strconv.Itoa(x+1) + ";" + strconv.Itoa(y+1) + "M")
+	case MouseEncodingUTF8:
+		if x+33 >= 0x800 || y+33 >= 0x800 {
+			return nil
+		}
+		b := []byte("\x1b[M")
+		for _, v := range []int{code + 32, x + 33, y + 33} {
+			b = utf8.AppendRune(b, rune(v))
+		}
+		return b
+	}
+	if x+33 > 0xff || y+33 > 0xff {
+		return nil
+	}
+	return []byte{'\x1b', '[', 'M', byte(code + 32), byte(x + 33), byte(y + 33)}
+}
+
+func (e *Encoder) encodePaste(ev *EventPaste) []byte {
+	var b []byte
+	if (ev.Start() || ev.Whole()) && e.paste {
+		if s, ok := e.keys[tKeyCode{key: keyPasteStart}]; ok {
+			b = append(b, s...)
+		} else {
+			b = append(b, "\x1b[200~"...)
+		}
+	}
+	if ev.Whole() {
+		// terminals send carriage returns for new lines
+		b = append(b, strings.ReplaceAll(ev.Text(), "\n", "\r")...)
+	}
+	if (ev.End() || ev.Whole()) && e.paste {
+		if s, ok := e.keys[tKeyCode{key: keyPasteEnd}]; ok {
+			b = append(b, s...)
+		} else {
+			b = append(b, "\x1b[201~"...)
+		}
+	}
+	return b
+}
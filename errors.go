@@ -48,6 +48,10 @@ var (
 	// ErrEventQFull indicates that the event queue is full, and
 	// cannot accept more events.
 	ErrEventQFull = errors.New("event queue full")
+
+	// ErrNotSupported indicates that the screen does not support the
+	// operation, for example because it is not a terminal.
+	ErrNotSupported = errors.New("operation not supported")
 )
 
 // An EventError is an event representing some sort of error, and carries
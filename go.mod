@@ -6,9 +6,8 @@ require (
 	github.com/gdamore/encoding v1.0.1
 	github.com/lucasb-eyer/go-colorful v1.2.0
 	github.com/mattn/go-runewidth v0.0.16
+	github.com/rivo/uniseg v0.4.3
 	golang.org/x/sys v0.34.0
 	golang.org/x/term v0.33.0
 	golang.org/x/text v0.27.0
 )
-
-require github.com/rivo/uniseg v0.4.3 // indirect
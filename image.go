@@ -0,0 +1,41 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// Image is an image that has been loaded into the terminal with
+// Screen.LoadImage.  The image data is transmitted to the terminal
+// once, and can then be placed on the screen any number of times.
+type Image interface {
+	// Place shows the image over the given region of cells, scaled
+	// to fill it.  If z is zero or greater, the image is drawn above
+	// the text, and the cells are locked (see Screen.LockRegion) until
+	// the placement is deleted.  If z is negative, the image is drawn
+	// below the text, and the cells are left alone.  Images with a
+	// higher z are drawn above those with lower ones.  As with other
+	// changes, the placement is only visible after Screen.Show.
+	Place(x, y, cols, rows, z int) ImagePlacement
+
+	// Free deletes all placements of the image, and releases the
+	// image data held by the terminal.  The image cannot be placed
+	// again afterwards.
+	Free()
+}
+
+// ImagePlacement is a handle for an Image placed on the screen.
+type ImagePlacement interface {
+	// Delete removes the placement from the screen, unlocking the
+	// cells under it.
+	Delete()
+}
@@ -24,7 +24,9 @@ import (
 // by a key release, but since terminal programs don't have a way to report
 // key release events, we usually get just one event.  If a key is held down
 // then the terminal may synthesize repeated key presses at some predefined
-// rate.  We have no control over that, nor visibility into it.
+// rate.  We have no control over that, nor visibility into it, unless the
+// terminal supports the kitty keyboard protocol, in which case repeat and
+// release events can be requested with SetKeyboardFlags.  See EventType.
 //
 // In some cases, we can have a modifier key, such as ModAlt, that can be
 // generated with a key press.  (This usually is represented by having the
@@ -47,6 +49,8 @@ type EventKey struct {
 	mod ModMask
 	key Key
 	ch  rune
+	et  KeyEventType
+	raw []byte
 }
 
 // When returns the time when this Event was created, which should closely
@@ -78,6 +82,22 @@ func (ev *EventKey) Modifiers() ModMask {
 	return ev.mod
 }
 
+// EventType returns whether this event reports a key press, an automatic
+// repeat of a held key, or a key release.  Most terminals can only report
+// key presses; repeat and release events are only delivered if they were
+// requested with SetKeyboardFlags and the terminal supports them.
+func (ev *EventKey) EventType() KeyEventType {
+	return ev.et
+}
+
+// Raw returns the bytes that were received from the terminal for this key,
+// including any escape sequence.  This is nil for keys that did not come
+// from a terminal byte stream, such as on Windows consoles, or for keys
+// that were created by the application.
+func (ev *EventKey) Raw() []byte {
+	return ev.raw
+}
+
 // KeyNames holds the written names of special keys. Useful to echo back a key
 // name, or to look up a key from a string value.
 var KeyNames = map[Key]string{
@@ -215,6 +235,12 @@ func (ev *EventKey) Name() string {
 	if ev.mod&ModMeta != 0 {
 		m = append(m, "Meta")
 	}
+	if ev.mod&ModSuper != 0 {
+		m = append(m, "Super")
+	}
+	if ev.mod&ModHyper != 0 {
+		m = append(m, "Hyper")
+	}
 	if ev.mod&ModCtrl != 0 {
 		m = append(m, "Ctrl")
 	}
@@ -258,6 +284,14 @@ func NewEventKey(k Key, ch rune, mod ModMask) *EventKey {
 	return &EventKey{t: time.Now(), key: k, ch: ch, mod: mod}
 }
 
+// newEventKeyType is like NewEventKey, but also records whether the event
+// is a press, repeat, or release.
+func newEventKeyType(k Key, ch rune, mod ModMask, et KeyEventType) *EventKey {
+	ev := NewEventKey(k, ch, mod)
+	ev.et = et
+	return ev
+}
+
 // ModMask is a mask of modifier keys.  Note that it will not always be
 // possible to report modifier keys.
 type ModMask int16
@@ -267,14 +301,39 @@ type ModMask int16
 // with Meta, and the lack of support for it on many/most platforms, the
 // current implementations never use it.  Instead, they use ModAlt, even for
 // events that could possibly have been distinguished from ModAlt.
+//
+// ModSuper, ModHyper, ModCapsLock and ModNumLock are only reported by
+// terminals that support the kitty keyboard protocol.
 const (
 	ModShift ModMask = 1 << iota
 	ModCtrl
 	ModAlt
 	ModMeta
+	ModSuper
+	ModHyper
+	ModCapsLock
+	ModNumLock
 	ModNone ModMask = 0
 )
 
+// KeyEventType indicates what kind of key activity an EventKey reports.
+type KeyEventType int
+
+const (
+	KeyEventPress   = KeyEventType(iota) // Key was pressed (the default)
+	KeyEventRepeat                       // Key is being held down, and auto-repeated
+	KeyEventRelease                      // Key was released
+)
+
+// KeyboardFlags are options to request additional keyboard reporting.
+// These can be ORed together.  Key presses are always reported.
+type KeyboardFlags int
+
+const (
+	KeyRepeatEvents  = KeyboardFlags(1) // Report auto-repeat of held keys
+	KeyReleaseEvents = KeyboardFlags(2) // Report key releases
+)
+
 // Key is a generic value for representing keys, and especially special
 // keys (function keys, cursor movement keys, etc.)  For normal keys, like
 // ASCII letters, we use KeyRune, and then expect the application to
@@ -0,0 +1,232 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keymap parses key binding strings such as "ctrl+x ctrl+s",
+// "alt+shift+Left" or "F5", and matches them against key events, including
+// sequences of several keys (chords).  This is intended to let applications
+// read their key bindings from configuration files.
+//
+// Terminals report the same key stroke in different ways, so events are
+// normalized before they are compared.  Control characters are treated as
+// the corresponding letter with Ctrl (KeyCtrlA is Ctrl+a), Backspace is the
+// same whether the terminal sends BS or DEL, Backtab is Shift+Tab, and Shift
+// is folded into the character for printable keys (Shift+a is "A").  As
+// most terminals cannot report Shift together with Ctrl for letters, case
+// is ignored for Ctrl+letter, unless Shift is given explicitly.
+package keymap
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Key is a single key stroke, in normal form.  Printable keys and keys
+// typed with Ctrl have Key set to tcell.KeyRune, and the character in Rune.
+// Other keys have a zero Rune.  Use NewKey or KeyOf to create these.
+type Key struct {
+	Key  tcell.Key
+	Rune rune
+	Mod  tcell.ModMask
+}
+
+// modMask holds the modifiers that are significant for bindings.  Lock keys
+// are ignored.
+const modMask = tcell.ModShift | tcell.ModCtrl | tcell.ModAlt | tcell.ModMeta |
+	tcell.ModSuper | tcell.ModHyper
+
+// ctrlRunes are the characters that, typed with Ctrl, give the control
+// characters from NUL up to US.
+const ctrlRunes = " abcdefghijklmnopqrstuvwxyz[\\]^_"
+
+// NewKey returns the normal form of the key stroke.  The arguments are
+// the same as for tcell.NewEventKey.
+func NewKey(k tcell.Key, r rune, mod tcell.ModMask) Key {
+	mod &= modMask
+	switch {
+	case k == tcell.KeyRune:
+	case k == tcell.KeyBacktab:
+		return Key{Key: tcell.KeyTab, Mod: mod | tcell.ModShift}
+	case k == tcell.KeyBackspace2:
+		return Key{Key: tcell.KeyBackspace, Mod: mod}
+	case k >= 0 && int(k) < len(ctrlRunes):
+		switch k {
+		case tcell.KeyBackspace, tcell.KeyTab, tcell.KeyEnter, tcell.KeyEsc:
+			if mod&tcell.ModCtrl == 0 {
+				// directly typeable, without Ctrl
+				return Key{Key: k, Mod: mod}
+			}
+		}
+		r = rune(ctrlRunes[k])
+		mod |= tcell.ModCtrl
+	default:
+		return Key{Key: k, Mod: mod}
+	}
+	if mod&tcell.ModShift != 0 {
+		// the character already reflects shift, except for letters
+		// typed with Ctrl
+		r = unicode.ToUpper(r)
+		mod &^= tcell.ModShift
+	}
+	return Key{Key: tcell.KeyRune, Rune: r, Mod: mod}
+}
+
+// KeyOf returns the normal form of the key stroke in the event.
+func KeyOf(ev *tcell.EventKey) Key {
+	return NewKey(ev.Key(), ev.Rune(), ev.Modifiers())
+}
+
+// Matches returns true if the event is this key stroke.
+func (k Key) Matches(ev *tcell.EventKey) bool {
+	return KeyOf(ev) == k
+}
+
+// keyNames are the names of the special keys, in the forms that we write.
+var keyNames = map[tcell.Key]string{
+	tcell.KeyHelp: "Help",
+}
+
+// namedKeys maps lower case names, including some common aliases, to keys.
+var namedKeys = map[string]Key{
+	"space":    {Key: tcell.KeyRune, Rune: ' '},
+	"escape":   {Key: tcell.KeyEsc},
+	"return":   {Key: tcell.KeyEnter},
+	"pageup":   {Key: tcell.KeyPgUp},
+	"pagedown": {Key: tcell.KeyPgDn},
+	"del":      {Key: tcell.KeyDelete},
+	"ins":      {Key: tcell.KeyInsert},
+	"bs":       {Key: tcell.KeyBackspace},
+}
+
+func init() {
+	for k, name := range tcell.KeyNames {
+		// control keys are written as Ctrl with a character, and
+		// the other names are aliases
+		if k >= tcell.KeyRune {
+			keyNames[k] = name
+		}
+	}
+	for k, name := range keyNames {
+		namedKeys[strings.ToLower(name)] = Key{Key: k}
+	}
+	namedKeys["backspace2"] = Key{Key: tcell.KeyBackspace}
+	namedKeys["backtab"] = Key{Key: tcell.KeyTab, Mod: tcell.ModShift}
+	for _, k := range []tcell.Key{tcell.KeyBackspace, tcell.KeyTab, tcell.KeyEnter, tcell.KeyEsc} {
+		keyNames[k] = tcell.KeyNames[k]
+		namedKeys[strings.ToLower(tcell.KeyNames[k])] = Key{Key: k}
+	}
+}
+
+// modNames are the names of the modifiers, in the order we write them.
+var modNames = []struct {
+	mod  tcell.ModMask
+	name string
+}{
+	{tcell.ModCtrl, "Ctrl"},
+	{tcell.ModAlt, "Alt"},
+	{tcell.ModMeta, "Meta"},
+	{tcell.ModSuper, "Super"},
+	{tcell.ModHyper, "Hyper"},
+	{tcell.ModShift, "Shift"},
+}
+
+// modAliases are other names accepted for modifiers.
+var modAliases = map[string]tcell.ModMask{
+	"control": tcell.ModCtrl,
+	"option":  tcell.ModAlt,
+	"cmd":     tcell.ModSuper,
+}
+
+// String returns the key stroke in the form accepted by ParseKey, such
+// as "Ctrl+x", "Alt+Left" or "F5".
+func (k Key) String() string {
+	var b strings.Builder
+	mod := k.Mod
+	if k.Key == tcell.KeyRune && mod&tcell.ModCtrl != 0 && unicode.IsUpper(k.Rune) {
+		// otherwise case is not significant with Ctrl
+		mod |= tcell.ModShift
+	}
+	for _, m := range modNames {
+		if mod&m.mod != 0 {
+			b.WriteString(m.name)
+			b.WriteByte('+')
+		}
+	}
+	switch {
+	case k.Key != tcell.KeyRune:
+		if name, ok := keyNames[k.Key]; ok {
+			b.WriteString(name)
+		} else {
+			fmt.Fprintf(&b, "Key[%d]", k.Key)
+		}
+	case k.Rune == ' ':
+		b.WriteString("Space")
+	default:
+		b.WriteRune(k.Rune)
+	}
+	return b.String()
+}
+
+// ParseKey parses a single key stroke, which is any number of modifiers
+// followed by a key, separated by '+' or '-'.  Modifiers are Ctrl, Alt,
+// Meta, Super, Hyper and Shift, and keys are either a single character,
+// or one of the names in tcell.KeyNames (such as "Enter", "PgDn" or "F12").
+// Names are not case sensitive, but characters are, except with Ctrl.
+func ParseKey(s string) (Key, error) {
+	rest := s
+	mod := tcell.ModNone
+outer:
+	for {
+		i := strings.IndexAny(rest, "+-")
+		if i < 1 || i == len(rest)-1 {
+			break
+		}
+		name := strings.ToLower(rest[:i])
+		for _, m := range modNames {
+			if name == strings.ToLower(m.name) {
+				mod |= m.mod
+				rest = rest[i+1:]
+				continue outer
+			}
+		}
+		if m, ok := modAliases[name]; ok {
+			mod |= m
+			rest = rest[i+1:]
+			continue
+		}
+		break
+	}
+
+	var r rune
+	if n := utf8.RuneCountInString(rest); n == 1 {
+		r, _ = utf8.DecodeRuneInString(rest)
+	} else if n == 7 && strings.HasPrefix(rest, "Rune[") && strings.HasSuffix(rest, "]") {
+		// as written by EventKey.Name
+		r, _ = utf8.DecodeRuneInString(rest[5:])
+	} else if k, ok := namedKeys[strings.ToLower(rest)]; ok {
+		return NewKey(k.Key, k.Rune, k.Mod|mod), nil
+	} else {
+		return Key{}, fmt.Errorf("keymap: unknown key %q", s)
+	}
+	if r < ' ' || r == 0x7f || r == utf8.RuneError {
+		return Key{}, fmt.Errorf("keymap: unknown key %q", s)
+	}
+	if mod&tcell.ModCtrl != 0 && mod&tcell.ModShift == 0 {
+		r = unicode.ToLower(r)
+	}
+	return NewKey(tcell.KeyRune, r, mod), nil
+}
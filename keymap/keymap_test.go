@@ -0,0 +1,181 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keymap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		s   string
+		key Key
+		str string
+	}{
+		{"F5", Key{Key: tcell.KeyF5}, "F5"},
+		{"ctrl+x", Key{Key: tcell.KeyRune, Rune: 'x', Mod: tcell.ModCtrl}, "Ctrl+x"},
+		{"Ctrl-X", Key{Key: tcell.KeyRune, Rune: 'x', Mod: tcell.ModCtrl}, "Ctrl+x"},
+		{"ctrl+shift+x", Key{Key: tcell.KeyRune, Rune: 'X', Mod: tcell.ModCtrl}, "Ctrl+Shift+X"},
+		{"alt+shift+Left", Key{Key: tcell.KeyLeft, Mod: tcell.ModAlt | tcell.ModShift}, "Alt+Shift+Left"},
+		{"shift+a", Key{Key: tcell.KeyRune, Rune: 'A'}, "A"},
+		{"Alt+Rune[a]", Key{Key: tcell.KeyRune, Rune: 'a', Mod: tcell.ModAlt}, "Alt+a"},
+		{"ctrl++", Key{Key: tcell.KeyRune, Rune: '+', Mod: tcell.ModCtrl}, "Ctrl++"},
+		{"-", Key{Key: tcell.KeyRune, Rune: '-'}, "-"},
+		{"ctrl+space", Key{Key: tcell.KeyRune, Rune: ' ', Mod: tcell.ModCtrl}, "Ctrl+Space"},
+		{"backtab", Key{Key: tcell.KeyTab, Mod: tcell.ModShift}, "Shift+Tab"},
+		{"pagedown", Key{Key: tcell.KeyPgDn}, "PgDn"},
+		{"Backspace2", Key{Key: tcell.KeyBackspace}, "Backspace"},
+		{"ESC", Key{Key: tcell.KeyEsc}, "Esc"},
+		{"ctrl+[", Key{Key: tcell.KeyRune, Rune: '[', Mod: tcell.ModCtrl}, "Ctrl+["},
+	}
+	for _, tc := range tests {
+		k, err := ParseKey(tc.s)
+		if err != nil {
+			t.Errorf("%q: %v", tc.s, err)
+			continue
+		}
+		if k != tc.key {
+			t.Errorf("%q: got %+v, expected %+v", tc.s, k, tc.key)
+		}
+		if k.String() != tc.str {
+			t.Errorf("%q: got %q, expected %q", tc.s, k.String(), tc.str)
+		}
+		if k2, err := ParseKey(k.String()); err != nil || k2 != k {
+			t.Errorf("%q: does not round trip: %+v %v", tc.s, k2, err)
+		}
+	}
+	for _, s := range []string{"", "foo", "ctrl+", "hyper+foo", "ctrl+shift+"} {
+		if _, err := ParseKey(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestKeyOf(t *testing.T) {
+	tests := []struct {
+		ev  *tcell.EventKey
+		key string
+	}{
+		{tcell.NewEventKey(tcell.KeyRune, 1, tcell.ModNone), "ctrl+a"},
+		{tcell.NewEventKey(tcell.KeyCtrlA, 0, tcell.ModCtrl), "ctrl+a"},
+		{tcell.NewEventKey(tcell.KeyCtrlA, 0, tcell.ModCtrl|tcell.ModShift), "ctrl+shift+a"},
+		{tcell.NewEventKey(tcell.KeyBackspace, 0, tcell.ModNone), "Backspace"},
+		{tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone), "Backspace"},
+		{tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModAlt), "alt+backspace"},
+		{tcell.NewEventKey(tcell.KeyCtrlH, 0, tcell.ModCtrl), "ctrl+h"},
+		{tcell.NewEventKey(tcell.KeyBacktab, 0, tcell.ModNone), "shift+tab"},
+		{tcell.NewEventKey(tcell.KeyRune, 'A', tcell.ModNone), "A"},
+		{tcell.NewEventKey(tcell.KeyRune, 'a', tcell.ModShift), "A"},
+		{tcell.NewEventKey(tcell.KeyRune, '!', tcell.ModShift), "!"},
+		{tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModAlt|tcell.ModNumLock), "alt+x"},
+		{tcell.NewEventKey(tcell.KeyF5, 0, tcell.ModNone), "F5"},
+	}
+	for _, tc := range tests {
+		k, err := ParseKey(tc.key)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.key, err)
+		}
+		if !k.Matches(tc.ev) {
+			t.Errorf("%s: got %v, expected %v", tc.ev.Name(), KeyOf(tc.ev), k)
+		}
+	}
+	if k, _ := ParseKey("ctrl+h"); k.Matches(tcell.NewEventKey(tcell.KeyBackspace, 0, tcell.ModNone)) {
+		t.Errorf("Backspace matched ctrl+h")
+	}
+}
+
+func TestParse(t *testing.T) {
+	b, err := Parse(" ctrl+x   ctrl+s ")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(b) != 2 || b.String() != "Ctrl+x Ctrl+s" {
+		t.Errorf("Bad binding: %v", b)
+	}
+	if _, err := Parse("  "); err == nil {
+		t.Errorf("Expected error for empty binding")
+	}
+	if _, err := Parse("ctrl+x bogus"); err == nil {
+		t.Errorf("Expected error for bad key")
+	}
+}
+
+func TestMap(t *testing.T) {
+	m := NewMap()
+	var ran []string
+	bind := func(s string) {
+		if err := m.BindString(s, func() { ran = append(ran, s) }); err != nil {
+			t.Fatalf("Failed to bind %q: %v", s, err)
+		}
+	}
+	bind("ctrl+x ctrl+s")
+	bind("ctrl+x ctrl+c")
+	bind("F5")
+	bind("q")
+
+	key := func(k tcell.Key, r rune, mod tcell.ModMask) bool {
+		return m.HandleEvent(tcell.NewEventKey(k, r, mod))
+	}
+	if !key(tcell.KeyRune, 0x18, tcell.ModNone) {
+		t.Errorf("Prefix not used")
+	}
+	if p := m.Pending(); p.String() != "Ctrl+x" {
+		t.Errorf("Bad pending: %v", p)
+	}
+	if !key(tcell.KeyCtrlS, 0, tcell.ModCtrl) {
+		t.Errorf("Binding not used")
+	}
+	if len(ran) != 1 || ran[0] != "ctrl+x ctrl+s" || m.Pending() != nil {
+		t.Errorf("Bad actions: %v", ran)
+	}
+
+	// a key that does not continue the sequence starts over
+	key(tcell.KeyCtrlX, 0, tcell.ModCtrl)
+	if !key(tcell.KeyF5, 0, tcell.ModNone) || len(ran) != 2 || ran[1] != "F5" {
+		t.Errorf("Bad actions: %v", ran)
+	}
+	key(tcell.KeyCtrlX, 0, tcell.ModCtrl)
+	if key(tcell.KeyRune, 'z', tcell.ModNone) || m.Pending() != nil {
+		t.Errorf("Unbound key used")
+	}
+
+	// sequences time out
+	m.Timeout = 10 * time.Millisecond
+	key(tcell.KeyCtrlX, 0, tcell.ModCtrl)
+	time.Sleep(20 * time.Millisecond)
+	if key(tcell.KeyRune, 'c', tcell.ModCtrl) || len(ran) != 2 {
+		t.Errorf("Sequence did not time out: %v", ran)
+	}
+	if !key(tcell.KeyRune, 'q', tcell.ModNone) || len(ran) != 3 {
+		t.Errorf("Bad actions: %v", ran)
+	}
+
+	// conflicting bindings are replaced
+	bind("ctrl+x")
+	if got := m.Bindings(); len(got) != 3 || got[0].String() != "Ctrl+x" ||
+		got[1].String() != "F5" || got[2].String() != "q" {
+		t.Errorf("Bad bindings: %v", got)
+	}
+	m.Unbind(MustParse("q"))
+	if key(tcell.KeyRune, 'q', tcell.ModNone) || len(m.Bindings()) != 2 {
+		t.Errorf("Binding not removed")
+	}
+	if m.HandleEvent(tcell.NewEventInterrupt(nil)) {
+		t.Errorf("Non-key event used")
+	}
+}
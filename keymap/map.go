@@ -0,0 +1,250 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keymap
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Binding is a sequence of one or more key strokes, typed one after the
+// other.
+type Binding []Key
+
+// Parse parses a binding, which is one or more key strokes (see ParseKey)
+// separated by spaces, such as "ctrl+x ctrl+s".
+func Parse(s string) (Binding, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, errors.New("keymap: empty binding")
+	}
+	b := make(Binding, 0, len(fields))
+	for _, f := range fields {
+		k, err := ParseKey(f)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, k)
+	}
+	return b, nil
+}
+
+// MustParse is like Parse, but panics if the binding cannot be parsed.
+// It is intended for bindings that are built into the program.
+func MustParse(s string) Binding {
+	b, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// String returns the binding in the form accepted by Parse.
+func (b Binding) String() string {
+	names := make([]string, 0, len(b))
+	for _, k := range b {
+		names = append(names, k.String())
+	}
+	return strings.Join(names, " ")
+}
+
+// DefaultTimeout is how long a Map waits for the next key of a sequence
+// by default.
+const DefaultTimeout = time.Second
+
+// Map holds bindings, and runs their actions as key events arrive.  When
+// the first keys of a sequence have been typed, the Map waits for the
+// next one, for at most Timeout.  A Map is safe to use from multiple
+// goroutines, and its HandleEvent method is like that of the views
+// widgets, so it can be called from one.
+type Map struct {
+	// Timeout is the most time allowed between the keys of a sequence.
+	// If zero, there is no limit.
+	Timeout time.Duration
+
+	root    *node
+	pending Binding
+	last    time.Time
+	sync.Mutex
+}
+
+type node struct {
+	action func()
+	next   map[Key]*node
+}
+
+// NewMap returns an empty Map, with the default timeout.
+func NewMap() *Map {
+	return &Map{Timeout: DefaultTimeout, root: &node{}}
+}
+
+// Bind binds the action to the key sequence.  Any binding that conflicts
+// with it, because one is the start of the other, is removed.
+func (m *Map) Bind(b Binding, action func()) {
+	if len(b) == 0 {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	n := m.root
+	for _, k := range b {
+		if n.next == nil {
+			n.next = make(map[Key]*node)
+		}
+		nn := n.next[k]
+		if nn == nil || nn.action != nil {
+			nn = &node{}
+			n.next[k] = nn
+		}
+		n = nn
+	}
+	n.action = action
+	n.next = nil
+	m.pending = nil
+}
+
+// BindString is like Bind, but parses the binding first.
+func (m *Map) BindString(s string, action func()) error {
+	b, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	m.Bind(b, action)
+	return nil
+}
+
+// Unbind removes the binding for the key sequence, if there is one.
+func (m *Map) Unbind(b Binding) {
+	m.Lock()
+	defer m.Unlock()
+	m.pending = nil
+	m.root.remove(b)
+}
+
+// remove removes the binding, and returns true if this node is now unused.
+func (n *node) remove(b Binding) bool {
+	if len(b) == 0 {
+		n.action = nil
+		return n.next == nil
+	}
+	if nn := n.next[b[0]]; nn != nil && nn.remove(b[1:]) {
+		delete(n.next, b[0])
+		if len(n.next) == 0 {
+			n.next = nil
+		}
+	}
+	return n.action == nil && n.next == nil
+}
+
+// Bindings returns all of the bound key sequences, sorted by their names.
+func (m *Map) Bindings() []Binding {
+	m.Lock()
+	defer m.Unlock()
+	var all []Binding
+	var walk func(n *node, prefix Binding)
+	walk = func(n *node, prefix Binding) {
+		if n.action != nil {
+			all = append(all, append(Binding{}, prefix...))
+		}
+		for k, nn := range n.next {
+			walk(nn, append(prefix, k))
+		}
+	}
+	walk(m.root, nil)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].String() < all[j].String()
+	})
+	return all
+}
+
+// Pending returns the keys typed so far of an incomplete sequence, for
+// example to display them to the user.  It returns nil if there are none.
+func (m *Map) Pending() Binding {
+	m.Lock()
+	defer m.Unlock()
+	if m.expired(time.Now()) {
+		m.pending = nil
+	}
+	return append(Binding(nil), m.pending...)
+}
+
+// Reset abandons any incomplete sequence.
+func (m *Map) Reset() {
+	m.Lock()
+	m.pending = nil
+	m.Unlock()
+}
+
+func (m *Map) expired(now time.Time) bool {
+	return len(m.pending) > 0 && m.Timeout > 0 && now.Sub(m.last) > m.Timeout
+}
+
+// lookup finds the node for the sequence, or nil if there is none.
+func (m *Map) lookup(b Binding) *node {
+	n := m.root
+	for _, k := range b {
+		if n = n.next[k]; n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// HandleEvent handles a key event.  If it completes a binding, then the
+// action is run.  It returns true if the event was used, either to run an
+// action, or as part of an incomplete sequence.  If a key does not
+// continue the incomplete sequence, that sequence is abandoned, and the
+// key is treated as the start of a new one.  Key releases, and other
+// events, are ignored.
+func (m *Map) HandleEvent(ev tcell.Event) bool {
+	kev, ok := ev.(*tcell.EventKey)
+	if !ok || kev.EventType() == tcell.KeyEventRelease {
+		return false
+	}
+	k := KeyOf(kev)
+
+	m.Lock()
+	if m.expired(kev.When()) {
+		m.pending = nil
+	}
+	n := m.lookup(append(m.pending, k))
+	if n == nil && len(m.pending) > 0 {
+		m.pending = nil
+		n = m.lookup(Binding{k})
+	}
+	if n == nil {
+		m.Unlock()
+		return false
+	}
+	if n.next != nil {
+		m.pending = append(m.pending, k)
+		m.last = kev.When()
+		m.Unlock()
+		return true
+	}
+	m.pending = nil
+	action := n.action
+	m.Unlock()
+
+	if action != nil {
+		action()
+	}
+	return true
+}
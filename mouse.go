@@ -15,6 +15,7 @@
 package tcell
 
 import (
+	"sync"
 	"time"
 )
 
@@ -33,13 +34,21 @@ import (
 // and some cannot report motion events unless a button is pressed.
 //
 // Applications can inspect the time between events to resolve double or
-// triple clicks.
+// triple clicks, or can ask the screen to do so with SetMouseClickInterval.
+// In that case each event also reports its phase, the button that was
+// pressed or released, and how many times it was clicked.
 type EventMouse struct {
-	t   time.Time
-	btn ButtonMask
-	mod ModMask
-	x   int
-	y   int
+	t      time.Time
+	btn    ButtonMask
+	mod    ModMask
+	x      int
+	y      int
+	px     int
+	py     int
+	pixels bool
+	phase  MousePhase
+	button ButtonMask
+	clicks int
 }
 
 // When returns the time when this EventMouse was created.
@@ -64,6 +73,35 @@ func (ev *EventMouse) Position() (int, int) {
 	return ev.x, ev.y
 }
 
+// PixelPosition returns the mouse position in pixels, from the upper left
+// corner of the terminal, for a finer position than Position gives.  This is
+// only known for terminals that support pixel reporting (SGR-Pixels, mode
+// 1016), and ok is false otherwise.
+func (ev *EventMouse) PixelPosition() (x, y int, ok bool) {
+	return ev.px, ev.py, ev.pixels
+}
+
+// Phase returns what happened to the mouse: whether a button was pressed or
+// released, it moved with or without buttons held, or the wheel turned.
+// It is MousePhaseUnknown unless SetMouseClickInterval was used.
+func (ev *EventMouse) Phase() MousePhase {
+	return ev.phase
+}
+
+// Button returns the button that was pressed or released, or the wheel
+// motion, for such events.  Otherwise, it is ButtonNone.
+func (ev *EventMouse) Button() ButtonMask {
+	return ev.button
+}
+
+// Clicks returns the number of times the button has been clicked in quick
+// succession: 1 for a single click, 2 for a double click, and so on.  It is
+// reported for both the press and the release of the button, and is zero for
+// other events.
+func (ev *EventMouse) Clicks() int {
+	return ev.clicks
+}
+
 // NewEventMouse is used to create a new mouse event.  Applications
 // shouldn't need to use this; its mostly for screen implementors.
 func NewEventMouse(x, y int, btn ButtonMask, mod ModMask) *EventMouse {
@@ -101,3 +139,117 @@ const (
 	ButtonSecondary = Button2
 	ButtonMiddle    = Button3
 )
+
+// MouseShape is the shape of the mouse pointer.
+type MouseShape int
+
+// These are the shapes of the mouse pointer that can be requested with
+// SetMouseShape.  Not all terminals can show all of them.
+const (
+	MouseShapeDefault    MouseShape = iota // The usual pointer, normally an arrow.
+	MouseShapeText                         // An I-beam, for selecting text.
+	MouseShapePointer                      // A hand, for links.
+	MouseShapeCrosshair                    // A crosshair, for precise selection.
+	MouseShapeWait                         // Busy, such as an hourglass.
+	MouseShapeHelp                         // Help is available.
+	MouseShapeMove                         // Something can be moved.
+	MouseShapeNotAllowed                   // The action is not allowed.
+	MouseShapeResizeEW                     // Resize left and right, as for a vertical splitter.
+	MouseShapeResizeNS                     // Resize up and down, as for a horizontal splitter.
+	MouseShapeResizeNWSE                   // Resize diagonally, from the top left corner.
+	MouseShapeResizeNESW                   // Resize diagonally, from the top right corner.
+)
+
+// mouseShapeNames are the CSS names of the mouse shapes, which are also
+// understood by terminals.
+var mouseShapeNames = map[MouseShape]string{
+	MouseShapeDefault:    "default",
+	MouseShapeText:       "text",
+	MouseShapePointer:    "pointer",
+	MouseShapeCrosshair:  "crosshair",
+	MouseShapeWait:       "wait",
+	MouseShapeHelp:       "help",
+	MouseShapeMove:       "move",
+	MouseShapeNotAllowed: "not-allowed",
+	MouseShapeResizeEW:   "ew-resize",
+	MouseShapeResizeNS:   "ns-resize",
+	MouseShapeResizeNWSE: "nwse-resize",
+	MouseShapeResizeNESW: "nesw-resize",
+}
+
+// MousePhase describes what happened in a mouse event.
+type MousePhase int
+
+// These are the phases of mouse events.
+const (
+	MousePhaseUnknown MousePhase = iota // Not tracked (see SetMouseClickInterval).
+	MousePress                          // A button was pressed.
+	MouseRelease                        // A button was released.
+	MouseDrag                           // The mouse moved, with a button held.
+	MouseMove                           // The mouse moved, without any buttons.
+	MouseWheel                          // The wheel was turned.
+)
+
+// DefaultClickInterval is a reasonable value for SetMouseClickInterval.
+const DefaultClickInterval = 500 * time.Millisecond
+
+// mouseTracker works out the phase, changed button, and click count of
+// mouse events, from the buttons they report.  It is shared by all screens
+// so that these are the same everywhere.
+type mouseTracker struct {
+	interval time.Duration
+	held     ButtonMask
+	last     ButtonMask // last button pressed, and where and when
+	x, y     int
+	when     time.Time
+	clicks   int
+	sync.Mutex
+}
+
+func (m *mouseTracker) setInterval(interval time.Duration) {
+	m.Lock()
+	m.interval = interval
+	m.clicks = 0
+	m.Unlock()
+}
+
+// track fills in the details of the event, if tracking is enabled.
+func (m *mouseTracker) track(ev *EventMouse) {
+	m.Lock()
+	defer m.Unlock()
+	if m.interval <= 0 {
+		return
+	}
+	const wheels = WheelUp | WheelDown | WheelLeft | WheelRight
+	if ev.btn&wheels != 0 {
+		// wheel events do not tell us about the buttons
+		ev.phase = MouseWheel
+		ev.button = ev.btn & wheels
+		return
+	}
+	pressed := ev.btn &^ m.held
+	released := m.held &^ ev.btn
+	m.held = ev.btn
+	switch {
+	case pressed != 0:
+		ev.phase = MousePress
+		ev.button = pressed & -pressed
+		if ev.button == m.last && ev.x == m.x && ev.y == m.y && ev.t.Sub(m.when) <= m.interval {
+			m.clicks++
+		} else {
+			m.clicks = 1
+		}
+		m.last, m.x, m.y, m.when = ev.button, ev.x, ev.y, ev.t
+		ev.clicks = m.clicks
+	case released != 0:
+		ev.phase = MouseRelease
+		ev.button = released & -released
+		if ev.button == m.last {
+			ev.clicks = m.clicks
+		}
+	case ev.btn != 0:
+		ev.phase = MouseDrag
+	default:
+		ev.phase = MouseMove
+	}
+}
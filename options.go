@@ -0,0 +1,173 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"os"
+	"time"
+)
+
+// ScreenOption is an option that changes how a Screen behaves, given when
+// the Screen is created with NewScreenWithOptions or
+// NewTerminfoScreenWithOptions.  Options take precedence over the
+// environment variables that otherwise control the same behavior, so that
+// applications can configure each Screen without changing the environment
+// of the process.
+type ScreenOption func(*screenOptions)
+
+// defaultEscapeTimeout is how long we wait for the rest of an escape
+// sequence, before deciding that an ESC was just a key press.
+const defaultEscapeTimeout = 50 * time.Millisecond
+
+type screenOptions struct {
+	escTimeout time.Duration
+	queueSize  int
+	altScreen  *bool // nil if not specified
+	trueColor  *bool // nil if not specified
+	mouse      MouseFlags
+	paste      bool
+	focus      bool
+	charset    string
+	inline     int
+	syncOutput *bool // nil if not specified
+}
+
+// newScreenOptions returns the options, starting with defaults (taken
+// from the environment where appropriate).
+func newScreenOptions(opts []ScreenOption) screenOptions {
+	o := screenOptions{
+		escTimeout: defaultEscapeTimeout,
+		queueSize:  defaultQueueSize,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// useAltScreen reports whether the alternate screen should be used.  Without
+// an option, this is checked against the environment each time, as it was
+// before options existed.
+func (o *screenOptions) useAltScreen() bool {
+	if o.altScreen != nil {
+		return *o.altScreen
+	}
+	return os.Getenv("TCELL_ALTSCREEN") != "disable"
+}
+
+// WithEscapeTimeout sets how long to wait for the rest of an escape sequence
+// to arrive, before treating the ESC as a key press on its own.  The default
+// is 50 milliseconds, which may be too short for slow remote connections.
+// This has no effect on the Windows console, which does not use escape
+// sequences for input.
+func WithEscapeTimeout(d time.Duration) ScreenOption {
+	return func(o *screenOptions) {
+		if d > 0 {
+			o.escTimeout = d
+		}
+	}
+}
+
+// WithEventQueueSize sets the size of the event queue.  (See also
+// Screen.SetEventQueueSize, which can change it later.)
+func WithEventQueueSize(size int) ScreenOption {
+	return func(o *screenOptions) {
+		if size > 0 {
+			o.queueSize = size
+		}
+	}
+}
+
+// WithAltScreen determines whether the alternate screen is used.  By
+// default it is, unless TCELL_ALTSCREEN is set to "disable" at the time
+// the screen is initialized (or resumed).  Without the alternate screen,
+// the content is left on the terminal after the Screen is finalized.
+func WithAltScreen(on bool) ScreenOption {
+	return func(o *screenOptions) {
+		o.altScreen = &on
+	}
+}
+
+// WithTrueColor enables or disables 24-bit color, instead of inferring it
+// from the terminal description and the COLORTERM and TCELL_TRUECOLOR
+// environment variables.  When enabled for a terminal that does not
+// describe 24-bit color sequences, the ISO 8613-6 sequences are assumed.
+func WithTrueColor(on bool) ScreenOption {
+	return func(o *screenOptions) {
+		o.trueColor = &on
+	}
+}
+
+// WithMouse enables mouse reporting from the start, as if EnableMouse
+// were called with the given flags.
+func WithMouse(flags ...MouseFlags) ScreenOption {
+	var f MouseFlags
+	for _, flag := range flags {
+		f |= flag
+	}
+	if len(flags) == 0 {
+		f = MouseMotionEvents | MouseDragEvents | MouseButtonEvents
+	}
+	return func(o *screenOptions) {
+		o.mouse = f
+	}
+}
+
+// WithPaste enables bracketed paste from the start, as if EnablePaste
+// were called.
+func WithPaste() ScreenOption {
+	return func(o *screenOptions) {
+		o.paste = true
+	}
+}
+
+// WithFocus enables focus reporting from the start, as if EnableFocus
+// were called.
+func WithFocus() ScreenOption {
+	return func(o *screenOptions) {
+		o.focus = true
+	}
+}
+
+// WithFallbackCharset names the character set to use when the one named
+// by the locale (LC_ALL, LC_CTYPE or LANG) is not supported.  Without
+// this, Init fails with ErrNoCharset in that case.
+func WithFallbackCharset(charset string) ScreenOption {
+	return func(o *screenOptions) {
+		o.charset = charset
+	}
+}
+
+// WithInline makes the screen occupy just the given number of lines of the
+// terminal, starting at the cursor, instead of taking over all of it.
+// See NewInlineScreen for details.  The Windows console does not support
+// this, so NewScreenWithOptions uses a terminfo based screen instead there.
+func WithInline(lines int) ScreenOption {
+	return func(o *screenOptions) {
+		if lines > 0 {
+			o.inline = lines
+		}
+	}
+}
+
+// WithSyncOutput determines whether updates are wrapped in synchronized
+// output (DEC private mode 2026), instead of using it when the terminal
+// says it supports it, unless TCELL_SYNC is set to "disable".  Enabling
+// it assumes support, which terminals that lack it may not handle well.
+func WithSyncOutput(on bool) ScreenOption {
+	return func(o *screenOptions) {
+		o.syncOutput = &on
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"time"
+)
+
+// EventPaletteColor reports the color the terminal uses for an entry
+// in its palette.  It is sent in reply to QueryPaletteColor.
+type EventPaletteColor struct {
+	t     time.Time
+	index int
+	color Color
+}
+
+// NewEventPaletteColor returns a new EventPaletteColor.
+func NewEventPaletteColor(index int, color Color) *EventPaletteColor {
+	return &EventPaletteColor{t: time.Now(), index: index, color: color}
+}
+
+// When returns the time when this event was created.
+func (ev *EventPaletteColor) When() time.Time {
+	return ev.t
+}
+
+// Index returns the palette index.
+func (ev *EventPaletteColor) Index() int {
+	return ev.index
+}
+
+// Color returns the RGB color used for the palette entry.
+func (ev *EventPaletteColor) Color() Color {
+	return ev.color
+}
@@ -16,6 +16,7 @@ package tcell
 
 import (
 	"time"
+	"unicode/utf8"
 )
 
 // EventPaste is used to mark the start and end of a bracketed paste.
@@ -23,10 +24,17 @@ import (
 // An event with .Start() true will be sent to mark the start of a bracketed paste,
 // followed by a number of keys (string data) for the content, ending with the
 // an event with .End() true.
+//
+// If a paste limit was set with SetPasteLimit, then a single event is sent
+// for the entire paste instead.  For it .Whole() is true (and .Start() and
+// .End() are false), and .Text() returns the content.
 type EventPaste struct {
-	start bool
-	t     time.Time
-	data  []byte
+	start     bool
+	end       bool
+	whole     bool
+	t         time.Time
+	data      []byte
+	truncated bool
 }
 
 // When returns the time when this EventPaste was created.
@@ -41,12 +49,57 @@ func (ev *EventPaste) Start() bool {
 
 // End returns true if this is the end of a paste.
 func (ev *EventPaste) End() bool {
-	return !ev.start
+	return ev.end
+}
+
+// Whole returns true if this event holds an entire paste.
+func (ev *EventPaste) Whole() bool {
+	return ev.whole
+}
+
+// Text returns the pasted text, for an event that holds an entire paste.
+// Line endings are always reported as newlines.
+func (ev *EventPaste) Text() string {
+	return string(ev.data)
+}
+
+// Truncated returns true if the paste was larger than the paste limit,
+// and so some text was discarded.
+func (ev *EventPaste) Truncated() bool {
+	return ev.truncated
 }
 
 // NewEventPaste returns a new EventPaste.
 func NewEventPaste(start bool) *EventPaste {
-	return &EventPaste{t: time.Now(), start: start}
+	return &EventPaste{t: time.Now(), start: start, end: !start}
+}
+
+// NewEventPasteText returns a new EventPaste holding an entire paste.
+// Line endings are converted to newlines, and the text is cut short if it
+// is longer than limit bytes (unless limit is zero).
+func NewEventPasteText(text string, limit int) *EventPaste {
+	var data []byte
+	truncated := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c == '\r' {
+			if i+1 < len(text) && text[i+1] == '\n' {
+				continue
+			}
+			c = '\n'
+		}
+		if limit > 0 && len(data) >= limit {
+			// do not leave part of a character behind
+			for !utf8.RuneStart(c) && len(data) > 0 {
+				c = data[len(data)-1]
+				data = data[:len(data)-1]
+			}
+			truncated = true
+			break
+		}
+		data = append(data, c)
+	}
+	return &EventPaste{t: time.Now(), whole: true, data: data, truncated: truncated}
 }
 
 // NewEventClipboard returns a new NewEventClipboard with a data payload
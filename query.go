@@ -0,0 +1,95 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+	"time"
+)
+
+// ReplyKind is the kind of control sequence a terminal replies with.
+type ReplyKind int
+
+const (
+	ReplyCSI ReplyKind = iota + 1 // control sequence (ESC [ ... final)
+	ReplyOSC                      // operating system command (ESC ] ... ST)
+	ReplyDCS                      // device control string (ESC P ... ST)
+	ReplyAPC                      // application program command (ESC _ ... ST)
+)
+
+// ReplyMatcher describes the reply expected to a query.  The content of
+// a reply is what follows the introducer, without the terminator.  For
+// CSI replies, this is the parameters followed by the final byte.
+type ReplyMatcher struct {
+	Kind   ReplyKind // the kind of control sequence
+	Prefix string    // the content must start with this
+	Final  byte      // for CSI, the final byte, if not zero
+}
+
+// MatchCSI returns a matcher for a CSI reply, whose parameters begin with
+// the prefix, and with the given final byte.  For example, the reply to a
+// request for the primary device attributes is matched by MatchCSI("?", 'c').
+func MatchCSI(prefix string, final byte) ReplyMatcher {
+	return ReplyMatcher{Kind: ReplyCSI, Prefix: prefix, Final: final}
+}
+
+// MatchOSC returns a matcher for an OSC reply starting with the prefix,
+// for example "11;" for the reply to a query of the background color.
+func MatchOSC(prefix string) ReplyMatcher {
+	return ReplyMatcher{Kind: ReplyOSC, Prefix: prefix}
+}
+
+// MatchDCS returns a matcher for a DCS reply starting with the prefix,
+// for example ">|" for the reply to XTVERSION.
+func MatchDCS(prefix string) ReplyMatcher {
+	return ReplyMatcher{Kind: ReplyDCS, Prefix: prefix}
+}
+
+func (m ReplyMatcher) match(kind ReplyKind, data []byte) bool {
+	if m.Kind != kind || !bytes.HasPrefix(data, []byte(m.Prefix)) {
+		return false
+	}
+	if kind == ReplyCSI && m.Final != 0 && data[len(data)-1] != m.Final {
+		return false
+	}
+	return true
+}
+
+// EventReply carries the reply to a query sent with PostQuery.
+type EventReply struct {
+	t    time.Time
+	kind ReplyKind
+	data []byte
+}
+
+// NewEventReply returns a new EventReply.
+func NewEventReply(kind ReplyKind, data []byte) *EventReply {
+	return &EventReply{t: time.Now(), kind: kind, data: data}
+}
+
+// When returns the time when this event was created.
+func (ev *EventReply) When() time.Time {
+	return ev.t
+}
+
+// Kind returns the kind of control sequence of the reply.
+func (ev *EventReply) Kind() ReplyKind {
+	return ev.kind
+}
+
+// Data returns the content of the reply, as for Screen.Query.
+func (ev *EventReply) Data() []byte {
+	return ev.data
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"sync"
+)
+
+// EventQueueStats reports what the event queue has done to keep up, when
+// events arrive faster than the application handles them.
+type EventQueueStats struct {
+	Size      int    // the capacity of the queue
+	Pending   int    // events waiting to be handled
+	Coalesced uint64 // motion and resize events merged with a later one
+	Dropped   uint64 // motion and resize events discarded for lack of room
+}
+
+// defaultQueueSize is the number of events that can be queued by default.
+const defaultQueueSize = 10
+
+// queuedEvent is an event in the queue, noting if it is transient (mouse
+// motion or a resize), and so can be replaced by a later event.
+type queuedEvent struct {
+	ev        Event
+	transient bool
+}
+
+// eventQueue holds events until the application takes them, in the order
+// they arrived.  Consecutive mouse motion and resize events are merged,
+// keeping only the latest, and if the queue fills up they are discarded to
+// make room for other events, so that a flood of mouse motion cannot hold
+// up keys.
+type eventQueue struct {
+	size    int
+	events  []queuedEvent
+	buttons ButtonMask // buttons held, in the last mouse event
+	ready   chan struct{}
+	room    chan struct{}
+	stats   EventQueueStats
+	sync.Mutex
+}
+
+func newEventQueue(size int) *eventQueue {
+	return &eventQueue{
+		size:  size,
+		ready: make(chan struct{}, 1),
+		room:  make(chan struct{}, 1),
+	}
+}
+
+// wake wakes up one waiter on the channel, if there is one.
+func wake(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+func (q *eventQueue) setSize(size int) {
+	if size < 1 {
+		size = defaultQueueSize
+	}
+	q.Lock()
+	q.size = size
+	q.Unlock()
+	wake(q.room)
+}
+
+// add adds the event to the queue, if there is room for it (or it can be
+// merged with one that is there).  The lock must be held.
+func (q *eventQueue) add(ev Event) bool {
+	switch ev := ev.(type) {
+	case *EventMouse:
+		const wheels = WheelUp | WheelDown | WheelLeft | WheelRight
+		// The buttons held are only updated once the event is queued,
+		// as a press or release that has to wait for room is retried.
+		motion := ev.btn&wheels == 0 && ev.btn == q.buttons
+		if motion {
+			if n := len(q.events); n > 0 && q.events[n-1].transient {
+				if last, ok := q.events[n-1].ev.(*EventMouse); ok && last.mod == ev.mod {
+					q.events[n-1].ev = ev
+					q.stats.Coalesced++
+					return true
+				}
+			}
+		}
+		if !q.makeRoom() {
+			return false
+		}
+		q.events = append(q.events, queuedEvent{ev: ev, transient: motion})
+		if ev.btn&wheels == 0 {
+			q.buttons = ev.btn
+		}
+		return true
+	case *EventResize:
+		if n := len(q.events); n > 0 {
+			if _, ok := q.events[n-1].ev.(*EventResize); ok {
+				q.events[n-1].ev = ev
+				q.stats.Coalesced++
+				return true
+			}
+		}
+		if !q.makeRoom() {
+			return false
+		}
+		q.events = append(q.events, queuedEvent{ev: ev, transient: true})
+		return true
+	}
+	if !q.makeRoom() {
+		return false
+	}
+	q.events = append(q.events, queuedEvent{ev: ev})
+	return true
+}
+
+// makeRoom makes sure there is room for another event, discarding the
+// oldest motion or resize event if need be.  The lock must be held.
+func (q *eventQueue) makeRoom() bool {
+	if len(q.events) < q.size {
+		return true
+	}
+	for i, qe := range q.events {
+		if qe.transient {
+			q.events = append(q.events[:i], q.events[i+1:]...)
+			q.stats.Dropped++
+			return true
+		}
+	}
+	return false
+}
+
+// post adds an event to the queue.  If there is no room for it, then it
+// waits for room until stop is closed, or if stop is nil, it fails with
+// ErrEventQFull.
+func (q *eventQueue) post(ev Event, stop <-chan struct{}) error {
+	for {
+		q.Lock()
+		added := q.add(ev)
+		full := len(q.events) >= q.size
+		q.Unlock()
+		if added {
+			wake(q.ready)
+			if !full {
+				// pass it on to anyone else waiting for room
+				wake(q.room)
+			}
+			return nil
+		}
+		if stop == nil {
+			return ErrEventQFull
+		}
+		select {
+		case <-q.room:
+		case <-stop:
+			return ErrNoScreen
+		}
+	}
+}
+
+// take removes the next event from the queue, or returns nil if it is empty.
+func (q *eventQueue) take() Event {
+	q.Lock()
+	defer q.Unlock()
+	if len(q.events) == 0 {
+		return nil
+	}
+	ev := q.events[0].ev
+	q.events[0].ev = nil
+	q.events = q.events[1:]
+	if len(q.events) > 0 {
+		wake(q.ready)
+	}
+	wake(q.room)
+	return ev
+}
+
+// poll waits for the next event, until either quit or stop is closed.
+func (q *eventQueue) poll(quit, stop <-chan struct{}) Event {
+	for {
+		if ev := q.take(); ev != nil {
+			return ev
+		}
+		select {
+		case <-q.ready:
+		case <-quit:
+			return nil
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+func (q *eventQueue) pending() bool {
+	q.Lock()
+	defer q.Unlock()
+	return len(q.events) > 0
+}
+
+func (q *eventQueue) getStats() EventQueueStats {
+	q.Lock()
+	defer q.Unlock()
+	stats := q.stats
+	stats.Size = q.size
+	stats.Pending = len(q.events)
+	return stats
+}
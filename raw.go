@@ -0,0 +1,45 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"time"
+)
+
+// EventRaw carries an escape sequence that was received from the terminal,
+// but that tcell does not understand.  Complete control sequences (CSI),
+// control strings (OSC, DCS and APC) and single shifts (SS3) are all
+// delivered this way, rather than as a series of key presses.  Applications
+// that need to support unusual terminals can decode these themselves.
+type EventRaw struct {
+	t    time.Time
+	data []byte
+}
+
+// NewEventRaw returns a new EventRaw holding the given bytes.
+func NewEventRaw(data []byte) *EventRaw {
+	return &EventRaw{t: time.Now(), data: data}
+}
+
+// When returns the time when this event was created.
+func (ev *EventRaw) When() time.Time {
+	return ev.t
+}
+
+// Data returns the complete sequence, including the introducer and any
+// terminator.
+func (ev *EventRaw) Data() []byte {
+	return ev.data
+}
@@ -14,7 +14,13 @@
 
 package tcell
 
-import "sync"
+import (
+	"context"
+	"image"
+	"iter"
+	"sync"
+	"time"
+)
 
 // Screen represents the physical (or emulated) screen.
 // This can be a terminal window or a physical console.  Platforms implement
@@ -63,6 +69,32 @@ type Screen interface {
 	// last column will be replaced with a single width space on output.
 	SetContent(x int, y int, primary rune, combining []rune, style Style)
 
+	// Put sets the contents of the given cell location to the first
+	// grapheme cluster of the string, such as a letter with its accents,
+	// an emoji with a skin tone, or a flag.  It returns the rest of the
+	// string, and the width of the cluster in cells, which is where the
+	// next cluster should be put.  The cluster is stored as the primary
+	// and combining runes reported by GetContent.
+	Put(x int, y int, str string, style Style) (string, int)
+
+	// PutStr puts the string, one grapheme cluster per cell (or two
+	// for wide ones), starting at the given location, and stopping at
+	// the right edge of the screen.  The default style is used.
+	PutStr(x int, y int, str string)
+
+	// PutStrStyled is like PutStr, but with the given style.
+	PutStrStyled(x int, y int, str string, style Style)
+
+	// SetWidthPolicy sets the policy used to determine how many cells
+	// characters occupy, notably those of East Asian ambiguous width.
+	// The width of existing content is updated.  The default policy is
+	// given by DefaultWidthPolicy.
+	SetWidthPolicy(WidthPolicy)
+
+	// GetWidthPolicy returns the policy used to determine how many cells
+	// characters occupy.
+	GetWidthPolicy() WidthPolicy
+
 	// SetStyle sets the default style to use when clearing the screen
 	// or when StyleDefault is specified.  If it is also StyleDefault,
 	// then whatever system/terminal default is relevant will be used.
@@ -103,6 +135,17 @@ type Screen interface {
 	// Furthermore, this will return nil if the Screen is finalized.
 	PollEvent() Event
 
+	// PollEventContext is like PollEvent, but it also stops waiting when
+	// the context is canceled, in which case it returns the context's error.
+	// If the screen is finalized, the error is ErrNoScreen.
+	PollEventContext(ctx context.Context) (Event, error)
+
+	// Events returns an iterator over events, as they arrive, for use with
+	// range.  The iteration ends when the context is canceled, or the
+	// screen is finalized.  Like PollEvent, this should not be used while
+	// ChannelEvents is running.
+	Events(ctx context.Context) iter.Seq[Event]
+
 	// HasPendingEvent returns true if PollEvent would return an event
 	// without blocking.  If the screen is stopped and PollEvent would
 	// return nil, then the return value from this function is unspecified.
@@ -128,6 +171,17 @@ type Screen interface {
 	// Goroutine is recommended to ensure no deadlock can occur.
 	PostEventWait(ev Event)
 
+	// SetEventQueueSize sets how many events can wait to be handled by the
+	// application.  Events are delivered in the order they arrived, but
+	// mouse motion and resize events are merged with later ones when the
+	// application falls behind, or discarded to make room if the queue is
+	// full.
+	SetEventQueueSize(size int)
+
+	// EventQueueStats reports on the state of the event queue, including
+	// how many events were merged or discarded, for diagnostic purposes.
+	EventQueueStats() EventQueueStats
+
 	// EnableMouse enables the mouse.  (If your terminal supports it.)
 	// If no flags are specified, then all events are reported, if the
 	// terminal supports them.
@@ -136,18 +190,41 @@ type Screen interface {
 	// DisableMouse disables the mouse.
 	DisableMouse()
 
+	// SetMouseClickInterval arranges for mouse events to report their phase
+	// (press, release, drag and so forth), the button that changed, and a
+	// click count.  Presses of the same button, at the same position, and
+	// within the interval of each other count as double or triple clicks.
+	// DefaultClickInterval is a good choice.  An interval of zero (the
+	// default) turns this off.
+	SetMouseClickInterval(interval time.Duration)
+
 	// EnablePaste enables bracketed paste mode, if supported.
 	EnablePaste()
 
 	// DisablePaste disables bracketed paste mode.
 	DisablePaste()
 
+	// SetPasteLimit arranges for each bracketed paste to be delivered as
+	// a single EventPaste holding all of the pasted text, instead of as key
+	// events between EventPastes marking the start and end of the paste.
+	// Text beyond limit bytes is discarded.  A limit of zero (the default)
+	// restores the delivery of pastes as key events.  If the end of a paste
+	// does not arrive in time, the text received so far is delivered.
+	SetPasteLimit(limit int)
+
 	// EnableFocus enables reporting of focus events, if your terminal supports it.
 	EnableFocus()
 
 	// DisableFocus disables reporting of focus events.
 	DisableFocus()
 
+	// SetKeyboardFlags requests additional keyboard reporting, such as
+	// key repeat and key release events.  Key presses are always reported.
+	// This only has an effect on terminals that are capable of reporting
+	// such events, such as those supporting the kitty keyboard protocol.
+	// Passing zero restores the default of reporting only key presses.
+	SetKeyboardFlags(KeyboardFlags)
+
 	// HasMouse returns true if the terminal (apparently) supports a
 	// mouse.  Note that the return value of true doesn't guarantee that
 	// a mouse/pointing device is present; a false return definitely
@@ -263,6 +340,24 @@ type Screen interface {
 	// cell prevents the cell from being redrawn.
 	LockRegion(x, y, width, height int, lock bool)
 
+	// DrawImage draws an image over the given region of cells, scaled
+	// to fit it while preserving its aspect ratio.  The cells are locked
+	// (see LockRegion) while the image is shown, and changing the content
+	// of any of them removes the image.  A nil image removes any images
+	// drawn within the region.  It returns false if the terminal cannot
+	// display images.  Support is detected from the terminal's reply to a
+	// query sent by Init, so see also ProbeCapabilities.
+	DrawImage(x, y, cols, rows int, img image.Image) bool
+
+	// LoadImage transmits an image to the terminal, using the kitty
+	// graphics protocol, and returns a handle that can be used to place
+	// it on the screen.  The image is only transmitted once, when it is
+	// first shown, however many times it is placed.  It returns nil if
+	// the terminal does not support the protocol.  As with DrawImage,
+	// support is detected from the terminal's reply to a query.
+	// All placements are removed when the screen is finalized or suspended.
+	LoadImage(img image.Image) Image
+
 	// Tty returns the underlying Tty. If the screen is not a terminal, the
 	// returned bool will be false
 	Tty() (Tty, bool)
@@ -273,6 +368,12 @@ type Screen interface {
 	// the results may vary.  Use of unicode characters may not be supported.
 	SetTitle(string)
 
+	// SetMouseShape changes the shape of the mouse pointer while it is over
+	// the screen, for example to show that text can be selected, or that a
+	// splitter can be dragged.  The shape is restored when the screen is
+	// finalized or suspended.  Terminals that cannot do this ignore it.
+	SetMouseShape(MouseShape)
+
 	// SetClipboard is used to post arbitrary data to the system clipboard.
 	// This need not be UTF-8 string data.  It's up to the recipient to decode the
 	// data meaningfully.  Terminals may prevent this for security reasons.
@@ -283,6 +384,98 @@ type Screen interface {
 	// EventPaste with the clipboard content as the Data() field.  Terminals may
 	// prevent this for security reasons.
 	GetClipboard()
+
+	// GetClipboardContext is like GetClipboard, but waits for the reply
+	// and returns the clipboard contents.  It returns an error if the
+	// context is done first, which is what happens if the terminal ignores
+	// the request, so a context with a deadline should be used.
+	GetClipboardContext(ctx context.Context) ([]byte, error)
+
+	// GetColorScheme requests the terminal's default foreground and
+	// background colors.  If the terminal replies, they are posted as an
+	// EventColorScheme.
+	GetColorScheme()
+
+	// EnableColorScheme asks the terminal to notify us when its colors
+	// change, for example when the user switches between a dark and a
+	// light theme.  Each change is posted as an EventColorScheme.
+	EnableColorScheme()
+
+	// DisableColorScheme stops notifications of color scheme changes.
+	DisableColorScheme()
+
+	// SetPaletteColor changes the color the terminal displays for the
+	// given palette index (such as 1 for ColorMaroon) to the given RGB
+	// color.  Palettes changed this way are restored when the screen
+	// is finalized or suspended.  Not all terminals support this, and
+	// some (such as the Linux console) only allow the first 16 colors
+	// to be changed.
+	SetPaletteColor(index int, c Color)
+
+	// ResetPalette restores the terminal's default palette, undoing changes
+	// made by other programs as well as by SetPaletteColor.  (Finalizing or
+	// suspending the screen only restores the palette if SetPaletteColor
+	// was used.)
+	ResetPalette()
+
+	// QueryPaletteColor asks the terminal for the color it displays for
+	// the given palette index.  If the terminal replies, the result is
+	// posted as an EventPaletteColor.
+	QueryPaletteColor(index int)
+
+	// QueryCursorPosition asks the terminal where the cursor is, using
+	// a cursor position report (DSR 6n).  The reply is posted as an
+	// EventCursorPosition.  Note that the cursor is moved while the screen
+	// is drawn, so this is mostly useful before drawing, or with inline
+	// screens.  While a reply is expected, input that looks like it (which
+	// includes F3 with modifiers on some terminals) is taken as the reply.
+	// If no reply arrives within a second, we stop waiting for it.
+	QueryCursorPosition()
+
+	// GetCursorPosition is like QueryCursorPosition, but waits for the
+	// reply, for up to the given timeout, and returns the position of the
+	// cursor instead of posting an event.  The bool is false if there was
+	// no reply in time.
+	GetCursorPosition(timeout time.Duration) (x, y int, ok bool)
+
+	// Query sends a query (any control sequence) to the terminal, and
+	// waits for the reply matched by m, returning its content.  The reply
+	// is not reported as input.  Replies are matched to queries in the
+	// order they were sent.  If the context is done first, its error is
+	// returned.  (A reply arriving shortly afterwards is still discarded.)
+	// Note that terminals ignore queries they don't understand, so
+	// a context with a deadline should be used.
+	Query(ctx context.Context, query string, m ReplyMatcher) ([]byte, error)
+
+	// PostQuery is like Query, but returns immediately, and the reply,
+	// if any, is posted as an EventReply.  As with QueryCursorPosition,
+	// we stop looking for the reply if it does not arrive within a second.
+	PostQuery(query string, m ReplyMatcher)
+
+	// PrintAbove prints lines of plain text above the screen, where they
+	// become part of the terminal's scrollback.  This is only meaningful
+	// for inline screens (see NewInlineScreen), and does nothing otherwise.
+	// The lines are printed the next time the screen is shown.
+	PrintAbove(lines ...string)
+
+	// Capabilities returns what is known about the terminal's features.
+	Capabilities() Capabilities
+
+	// ProbeCapabilities asks the terminal to report the features that
+	// it supports, using device attribute and mode queries, and waits at
+	// most the given time for the replies.  It returns the updated
+	// capabilities.  The screen must be initialized first.  Terminals
+	// that are not believed to understand these queries are not asked.
+	ProbeCapabilities(timeout time.Duration) Capabilities
+
+	// ProbeWidths learns how the terminal displays ambiguous width
+	// characters, by printing one and asking the terminal where the
+	// cursor went (DSR 6n).  The width policy is updated to match, and
+	// returned.  It waits at most for the given timeout for the reply,
+	// and leaves the policy alone if there is none.  This should be
+	// called after Init, and before drawing, as the probe disturbs the
+	// screen content.  (It is redrawn by the next Show.)
+	ProbeWidths(timeout time.Duration) WidthPolicy
 }
 
 // NewScreen returns a default Screen suitable for the user's terminal
@@ -298,6 +491,18 @@ func NewScreen() (Screen, error) {
 	}
 }
 
+// NewScreenWithOptions is like NewScreen, but the Screen is configured
+// with the given options.
+func NewScreenWithOptions(opts ...ScreenOption) (Screen, error) {
+	if s, _ := newConsoleScreen(opts); s != nil {
+		return s, nil
+	} else if s, e := newTerminfoScreen(opts); s != nil {
+		return s, nil
+	} else {
+		return nil, e
+	}
+}
+
 // MouseFlags are options to modify the handling of mouse events.
 // Actual events can be ORed together.
 type MouseFlags int
@@ -334,10 +539,13 @@ type screenImpl interface {
 	Size() (width, height int)
 	EnableMouse(...MouseFlags)
 	DisableMouse()
+	SetMouseClickInterval(interval time.Duration)
 	EnablePaste()
 	DisablePaste()
+	SetPasteLimit(limit int)
 	EnableFocus()
 	DisableFocus()
+	SetKeyboardFlags(KeyboardFlags)
 	HasMouse() bool
 	Colors() int
 	Show()
@@ -353,9 +561,27 @@ type screenImpl interface {
 	Beep() error
 	SetSize(int, int)
 	SetTitle(string)
+	SetMouseShape(MouseShape)
 	Tty() (Tty, bool)
+	DrawImage(x, y, cols, rows int, img image.Image) bool
+	LoadImage(image.Image) Image
 	SetClipboard([]byte)
 	GetClipboard()
+	GetColorScheme()
+	EnableColorScheme()
+	DisableColorScheme()
+	SetPaletteColor(int, Color)
+	ResetPalette()
+	QueryPaletteColor(int)
+	QueryCursorPosition()
+	GetCursorPosition(time.Duration) (int, int, bool)
+	Query(context.Context, string, ReplyMatcher) ([]byte, error)
+	PostQuery(string, ReplyMatcher)
+	GetClipboardContext(context.Context) ([]byte, error)
+	PrintAbove(lines ...string)
+	Capabilities() Capabilities
+	ProbeCapabilities(time.Duration) Capabilities
+	ProbeWidths(time.Duration) WidthPolicy
 
 	// Following methods are not part of the Screen api, but are used for interaction with
 	// the common layer code.
@@ -375,7 +601,7 @@ type screenImpl interface {
 	// EventQ delivers events.  Events are posted to this by the screen in response to
 	// key presses, resizes, etc.  Application code receives events from this via the
 	// Screen.PollEvent, Screen.ChannelEvents APIs.
-	EventQ() chan Event
+	EventQ() *eventQueue
 }
 
 type baseScreen struct {
@@ -409,6 +635,43 @@ func (b *baseScreen) SetContent(x, y int, mainc rune, combc []rune, st Style) {
 	b.Unlock()
 }
 
+func (b *baseScreen) Put(x, y int, str string, st Style) (string, int) {
+	cells := b.GetCells()
+	b.Lock()
+	defer b.Unlock()
+	return cells.Put(x, y, str, st)
+}
+
+func (b *baseScreen) PutStr(x, y int, str string) {
+	b.PutStrStyled(x, y, str, StyleDefault)
+}
+
+func (b *baseScreen) PutStrStyled(x, y int, str string, st Style) {
+	cells := b.GetCells()
+	b.Lock()
+	defer b.Unlock()
+	w, _ := cells.Size()
+	for str != "" && x < w {
+		var width int
+		str, width = cells.Put(x, y, str, st)
+		x += width
+	}
+}
+
+func (b *baseScreen) SetWidthPolicy(wp WidthPolicy) {
+	cells := b.GetCells()
+	b.Lock()
+	cells.SetWidthPolicy(wp)
+	b.Unlock()
+}
+
+func (b *baseScreen) GetWidthPolicy() WidthPolicy {
+	cells := b.GetCells()
+	b.Lock()
+	defer b.Unlock()
+	return cells.GetWidthPolicy()
+}
+
 func (b *baseScreen) GetContent(x, y int) (rune, []rune, Style, int) {
 	var primary rune
 	var combining []rune
@@ -440,50 +703,66 @@ func (b *baseScreen) LockRegion(x, y, width, height int, lock bool) {
 func (b *baseScreen) ChannelEvents(ch chan<- Event, quit <-chan struct{}) {
 	defer close(ch)
 	for {
+		ev := b.EventQ().poll(quit, b.StopQ())
+		if ev == nil {
+			return
+		}
 		select {
 		case <-quit:
 			return
 		case <-b.StopQ():
 			return
-		case ev := <-b.EventQ():
-			select {
-			case <-quit:
-				return
-			case <-b.StopQ():
-				return
-			case ch <- ev:
-			}
+		case ch <- ev:
 		}
 	}
 }
 
 func (b *baseScreen) PollEvent() Event {
-	select {
-	case <-b.StopQ():
-		return nil
-	case ev := <-b.EventQ():
-		return ev
+	return b.EventQ().poll(nil, b.StopQ())
+}
+
+func (b *baseScreen) PollEventContext(ctx context.Context) (Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if ev := b.EventQ().poll(ctx.Done(), b.StopQ()); ev != nil {
+		return ev, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, ErrNoScreen
+}
+
+func (b *baseScreen) Events(ctx context.Context) iter.Seq[Event] {
+	return func(yield func(Event) bool) {
+		for {
+			ev, err := b.PollEventContext(ctx)
+			if err != nil || !yield(ev) {
+				return
+			}
+		}
 	}
 }
 
 func (b *baseScreen) HasPendingEvent() bool {
-	return len(b.EventQ()) > 0
+	return b.EventQ().pending()
 }
 
 func (b *baseScreen) PostEventWait(ev Event) {
-	select {
-	case b.EventQ() <- ev:
-	case <-b.StopQ():
-	}
+	_ = b.EventQ().post(ev, b.StopQ())
 }
 
 func (b *baseScreen) PostEvent(ev Event) error {
-	select {
-	case b.EventQ() <- ev:
-		return nil
-	default:
-		return ErrEventQFull
-	}
+	return b.EventQ().post(ev, nil)
+}
+
+func (b *baseScreen) SetEventQueueSize(size int) {
+	b.EventQ().setSize(size)
+}
+
+func (b *baseScreen) EventQueueStats() EventQueueStats {
+	return b.EventQ().getStats()
 }
 
 func (b *baseScreen) SetCursorStyle(cs CursorStyle, ccs ...Color) {
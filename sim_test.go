@@ -15,7 +15,9 @@
 package tcell
 
 import (
+	"context"
 	"testing"
+	"time"
 )
 
 func mkTestScreen(t *testing.T, charset string) SimulationScreen {
@@ -160,3 +162,322 @@ func TestTitle(t *testing.T) {
 		t.Errorf("Title mismatched")
 	}
 }
+
+func TestPaletteColor(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	s.SetPaletteColor(1, ColorTeal)
+	s.QueryPaletteColor(1)
+	s.QueryPaletteColor(2)
+	s.ResetPalette()
+	s.QueryPaletteColor(1)
+	for _, want := range []Color{ColorTeal.TrueColor(), ColorGreen.TrueColor(), ColorMaroon.TrueColor()} {
+		ev, ok := s.PollEvent().(*EventPaletteColor)
+		if !ok {
+			t.Fatalf("Expected palette color event")
+		}
+		if ev.Color() != want {
+			t.Errorf("Wrong color for %d: %v != %v", ev.Index(), ev.Color(), want)
+		}
+	}
+}
+
+func TestPutClusters(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	clusters := []struct {
+		str   string
+		width int
+	}{
+		{"e\u0301", 1}, // e with combining acute
+		{"\U0001F468\u200d\U0001F469\u200d\U0001F467", 2}, // family (ZWJ sequence)
+		{"\U0001F1EF\U0001F1F5", 2},                       // flag of Japan
+		{"\U0001F44D\U0001F3FD", 2},                       // thumbs up, medium skin tone
+		{"\u2764\ufe0f", 2},                               // heart, emoji presentation
+		{"x", 1},
+	}
+	str := ""
+	for _, c := range clusters {
+		str += c.str
+	}
+	s.PutStr(0, 0, str)
+	s.Show()
+
+	cells, w, _ := s.GetContents()
+	x := 0
+	for _, c := range clusters {
+		mainc, combc, _, width := s.GetContent(x, 0)
+		if got := string(append([]rune{mainc}, combc...)); got != c.str {
+			t.Errorf("Cell %d: %q != %q", x, got, c.str)
+		}
+		if width != c.width {
+			t.Errorf("Cell %d: width %d != %d", x, width, c.width)
+		}
+		if got := string(cells[x].Runes); got != c.str {
+			t.Errorf("Physical cell %d: %q != %q", x, got, c.str)
+		}
+		x += width
+	}
+	if x >= w {
+		t.Fatalf("Screen too narrow")
+	}
+
+	rest, width := s.Put(0, 1, "\U0001F1EF\U0001F1F5ab", StyleDefault)
+	if rest != "ab" || width != 2 {
+		t.Errorf("Bad put: %q %d", rest, width)
+	}
+}
+
+func TestWidthPolicy(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	s.SetContent(0, 0, '\u2500', nil, StyleDefault)
+	s.SetContent(0, 1, 'α', []rune{'\u0301'}, StyleDefault)
+	if _, _, _, width := s.GetContent(0, 0); width != 1 {
+		t.Errorf("Ambiguous width not narrow: %d", width)
+	}
+
+	s.SetWidthPolicy(WidthPolicy{AmbiguousWide: true, Overrides: []WidthRange{{First: 'α', Last: 'ω', Width: 1}}})
+	if _, _, _, width := s.GetContent(0, 0); width != 2 {
+		t.Errorf("Ambiguous width not wide: %d", width)
+	}
+	if _, _, _, width := s.GetContent(0, 1); width != 1 {
+		t.Errorf("Override not applied: %d", width)
+	}
+	if _, width := s.Put(0, 2, "\u00a7", StyleDefault); width != 2 {
+		t.Errorf("Ambiguous width not wide: %d", width)
+	}
+	if wp := s.GetWidthPolicy(); !wp.AmbiguousWide || len(wp.Overrides) != 1 {
+		t.Errorf("Bad width policy: %v", wp)
+	}
+	if width := s.GetWidthPolicy().StringWidth("\u2500x\U0001F1EF\U0001F1F5"); width != 5 {
+		t.Errorf("Bad string width: %d", width)
+	}
+}
+
+func TestInjectPaste(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	s.EnablePaste()
+	go s.InjectPaste("a\nb")
+	if ev, ok := s.PollEvent().(*EventPaste); !ok || !ev.Start() || ev.End() {
+		t.Fatalf("Expected paste start")
+	}
+	for _, k := range []Key{KeyRune, KeyEnter, KeyRune} {
+		if ev, ok := s.PollEvent().(*EventKey); !ok || ev.Key() != k {
+			t.Fatalf("Expected key %d", k)
+		}
+	}
+	if ev, ok := s.PollEvent().(*EventPaste); !ok || !ev.End() || ev.Start() {
+		t.Fatalf("Expected paste end")
+	}
+
+	s.SetPasteLimit(5)
+	go s.InjectPaste("ab\r\ncdé")
+	ev, ok := s.PollEvent().(*EventPaste)
+	if !ok || !ev.Whole() || ev.Start() || ev.End() {
+		t.Fatalf("Expected whole paste")
+	}
+	if ev.Text() != "ab\ncd" || !ev.Truncated() {
+		t.Errorf("Bad paste: %q %v", ev.Text(), ev.Truncated())
+	}
+}
+
+func TestMouseClicks(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	check := func(phase MousePhase, button ButtonMask, clicks int) {
+		t.Helper()
+		ev, ok := s.PollEvent().(*EventMouse)
+		if !ok {
+			t.Fatalf("Expected mouse event")
+		}
+		if ev.Phase() != phase || ev.Button() != button || ev.Clicks() != clicks {
+			t.Errorf("Bad mouse event: phase %d button %d clicks %d", ev.Phase(), ev.Button(), ev.Clicks())
+		}
+	}
+
+	s.InjectMouse(1, 1, Button1, ModNone)
+	check(MousePhaseUnknown, ButtonNone, 0)
+
+	s.SetMouseClickInterval(time.Minute)
+	s.InjectMouse(1, 1, Button1, ModNone)
+	check(MousePress, Button1, 1)
+	s.InjectMouse(1, 1, ButtonNone, ModNone)
+	check(MouseRelease, Button1, 1)
+	s.InjectMouse(1, 1, Button1, ModNone)
+	check(MousePress, Button1, 2)
+	s.InjectMouse(1, 1, ButtonNone, ModNone)
+	check(MouseRelease, Button1, 2)
+	s.InjectMouse(1, 1, Button2, ModNone)
+	check(MousePress, Button2, 1)
+	s.InjectMouse(2, 1, Button2, ModNone)
+	check(MouseDrag, ButtonNone, 0)
+	s.InjectMouse(2, 1, WheelUp, ModNone)
+	check(MouseWheel, WheelUp, 0)
+	s.InjectMouse(3, 1, ButtonNone, ModNone)
+	check(MouseRelease, Button2, 1)
+	s.InjectMouse(4, 1, ButtonNone, ModNone)
+	check(MouseMove, ButtonNone, 0)
+	s.InjectMouse(4, 1, Button2, ModNone)
+	check(MousePress, Button2, 1)
+}
+
+func TestEventQueue(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	s.SetEventQueueSize(4)
+	s.InjectMouse(1, 1, Button1, ModNone)
+	for x := 2; x < 10; x++ {
+		s.InjectMouse(x, 1, Button1, ModNone)
+	}
+	_ = s.PostEvent(NewEventResize(10, 10))
+	_ = s.PostEvent(NewEventResize(20, 10))
+	s.InjectKey(KeyRune, 'a', ModNone)
+
+	if stats := s.EventQueueStats(); stats.Pending != 4 || stats.Coalesced != 8 || stats.Dropped != 0 {
+		t.Errorf("Bad stats: %+v", stats)
+	}
+	if ev, ok := s.PollEvent().(*EventMouse); !ok {
+		t.Fatalf("Expected mouse press")
+	} else if x, _ := ev.Position(); x != 1 {
+		t.Errorf("Bad press position: %d", x)
+	}
+	if ev, ok := s.PollEvent().(*EventMouse); !ok {
+		t.Fatalf("Expected mouse motion")
+	} else if x, _ := ev.Position(); x != 9 {
+		t.Errorf("Bad motion position: %d", x)
+	}
+	if ev, ok := s.PollEvent().(*EventResize); !ok {
+		t.Fatalf("Expected resize")
+	} else if w, _ := ev.Size(); w != 20 {
+		t.Errorf("Bad resize: %d", w)
+	}
+	if ev, ok := s.PollEvent().(*EventKey); !ok || ev.Rune() != 'a' {
+		t.Fatalf("Expected key last")
+	}
+	if s.HasPendingEvent() {
+		t.Errorf("Unexpected pending event")
+	}
+
+	// motion makes way for keys when the queue is full
+	s.InjectMouse(1, 1, ButtonNone, ModNone)
+	s.InjectMouse(2, 1, ButtonNone, ModNone)
+	s.InjectMouse(3, 1, ButtonNone, ModShift)
+	for _, r := range "bcd" {
+		s.InjectKey(KeyRune, r, ModNone)
+	}
+	if stats := s.EventQueueStats(); stats.Pending != 4 || stats.Dropped != 2 {
+		t.Errorf("Bad stats: %+v", stats)
+	}
+	if err := s.PostEvent(NewEventInterrupt(nil)); err != ErrEventQFull {
+		t.Errorf("Expected full queue: %v", err)
+	}
+	if ev, ok := s.PollEvent().(*EventMouse); !ok {
+		t.Fatalf("Expected mouse release")
+	} else if x, _ := ev.Position(); x != 1 {
+		t.Errorf("Bad release position: %d", x)
+	}
+	for _, r := range "bcd" {
+		if ev, ok := s.PollEvent().(*EventKey); !ok || ev.Rune() != r {
+			t.Fatalf("Expected key %q", r)
+		}
+	}
+
+	// keys are never reordered past other events
+	_ = s.PostEvent(NewEventFocus(true))
+	s.InjectKey(KeyRune, 'e', ModNone)
+	if _, ok := s.PollEvent().(*EventFocus); !ok {
+		t.Fatalf("Expected focus before key")
+	}
+	if ev, ok := s.PollEvent().(*EventKey); !ok || ev.Rune() != 'e' {
+		t.Fatalf("Expected key after focus")
+	}
+}
+
+func TestEventQueueBlockedPress(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	s.SetEventQueueSize(2)
+	s.InjectKey(KeyRune, 'a', ModNone)
+	s.InjectKey(KeyRune, 'b', ModNone)
+
+	// the press has to wait for room, but is still a press once queued
+	done := make(chan struct{})
+	go func() {
+		s.InjectMouse(1, 1, Button1, ModNone)
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if ev, ok := s.PollEvent().(*EventKey); !ok || ev.Rune() != 'a' {
+		t.Fatalf("Expected key first")
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Timeout waiting for press")
+	}
+
+	if err := s.PostEvent(NewEventKey(KeyRune, 'c', ModNone)); err != ErrEventQFull {
+		t.Errorf("Expected full queue: %v", err)
+	}
+	if stats := s.EventQueueStats(); stats.Dropped != 0 {
+		t.Errorf("Bad stats: %+v", stats)
+	}
+	if ev, ok := s.PollEvent().(*EventKey); !ok || ev.Rune() != 'b' {
+		t.Fatalf("Expected key second")
+	}
+	if ev, ok := s.PollEvent().(*EventMouse); !ok || ev.Buttons() != Button1 {
+		t.Fatalf("Expected mouse press")
+	}
+}
+
+func TestPollEventContext(t *testing.T) {
+	s := mkTestScreen(t, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if ev, err := s.PollEventContext(ctx); ev != nil || err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline: %v %v", ev, err)
+	}
+
+	s.InjectKey(KeyRune, 'a', ModNone)
+	if ev, err := s.PollEventContext(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	} else if ev, ok := ev.(*EventKey); !ok || ev.Rune() != 'a' {
+		t.Errorf("Expected key: %v", ev)
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	for _, r := range "bcd" {
+		s.InjectKey(KeyRune, r, ModNone)
+	}
+	var keys string
+	for ev := range s.Events(ctx) {
+		if ev, ok := ev.(*EventKey); ok {
+			keys += string(ev.Rune())
+		}
+		if len(keys) == 2 {
+			stop()
+		}
+	}
+	if keys != "bc" {
+		t.Errorf("Bad keys: %q", keys)
+	}
+	if ev, ok := s.PollEvent().(*EventKey); !ok || ev.Rune() != 'd' {
+		t.Errorf("Expected key left in queue")
+	}
+
+	s.Fini()
+	if _, err := s.PollEventContext(context.Background()); err != ErrNoScreen {
+		t.Errorf("Expected no screen: %v", err)
+	}
+}
@@ -15,7 +15,10 @@
 package tcell
 
 import (
+	"context"
+	"image"
 	"sync"
+	"time"
 	"unicode/utf8"
 
 	"golang.org/x/text/transform"
@@ -27,7 +30,7 @@ func NewSimulationScreen(charset string) SimulationScreen {
 	if charset == "" {
 		charset = "UTF-8"
 	}
-	ss := &simscreen{charset: charset}
+	ss := &simscreen{charset: charset, evch: newEventQueue(defaultQueueSize)}
 	ss.Screen = &baseScreen{screenImpl: ss}
 	return ss
 }
@@ -52,6 +55,12 @@ type SimulationScreen interface {
 	// InjectMouse injects a mouse event.
 	InjectMouse(x, y int, buttons ButtonMask, mod ModMask)
 
+	// InjectPaste injects text as if it were pasted into the terminal.
+	// If bracketed paste is enabled, it is delivered the same way a real
+	// terminal paste would be (see SetPasteLimit), otherwise it is
+	// delivered as key events.
+	InjectPaste(text string)
+
 	// GetContents returns screen contents as an array of
 	// cells, along with the physical width & height.   Note that the
 	// physical contents will be used until the next time SetSize()
@@ -64,6 +73,9 @@ type SimulationScreen interface {
 	// GetTitle gets the previously set title.
 	GetTitle() string
 
+	// GetMouseShape gets the previously set mouse pointer shape.
+	GetMouseShape() MouseShape
+
 	// GetClipboardData gets the actual data for the clipboard.
 	GetClipboardData() []byte
 }
@@ -87,7 +99,7 @@ type simscreen struct {
 	physh int
 	fini  bool
 	style Style
-	evch  chan Event
+	evch  *eventQueue
 	quit  chan struct{}
 
 	front     []SimCell
@@ -98,6 +110,10 @@ type simscreen struct {
 	cursorvis bool
 	mouse     bool
 	paste     bool
+	pasteMax  int
+	clicks    mouseTracker
+	kbdFlags  KeyboardFlags
+	palette   map[int]Color
 	charset   string
 	encoder   transform.Transformer
 	decoder   transform.Transformer
@@ -105,6 +121,7 @@ type simscreen struct {
 	fillstyle Style
 	fallback  map[rune]string
 	title     string
+	shape     MouseShape
 	clipboard []byte
 
 	Screen
@@ -112,7 +129,6 @@ type simscreen struct {
 }
 
 func (s *simscreen) Init() error {
-	s.evch = make(chan Event, 10)
 	s.quit = make(chan struct{})
 	s.fillchar = 'X'
 	s.fillstyle = StyleDefault
@@ -286,6 +302,10 @@ func (s *simscreen) EnableMouse(...MouseFlags) {
 	s.mouse = true
 }
 
+func (s *simscreen) SetMouseClickInterval(interval time.Duration) {
+	s.clicks.setInterval(interval)
+}
+
 func (s *simscreen) DisableMouse() {
 	s.mouse = false
 }
@@ -298,12 +318,24 @@ func (s *simscreen) DisablePaste() {
 	s.paste = false
 }
 
+func (s *simscreen) SetPasteLimit(limit int) {
+	s.Lock()
+	s.pasteMax = limit
+	s.Unlock()
+}
+
 func (s *simscreen) EnableFocus() {
 }
 
 func (s *simscreen) DisableFocus() {
 }
 
+func (s *simscreen) SetKeyboardFlags(flags KeyboardFlags) {
+	s.Lock()
+	s.kbdFlags = flags
+	s.Unlock()
+}
+
 func (s *simscreen) Size() (int, int) {
 	s.Lock()
 	w, h := s.back.Size()
@@ -326,14 +358,12 @@ func (s *simscreen) Colors() int {
 }
 
 func (s *simscreen) postEvent(ev Event) {
-	select {
-	case s.evch <- ev:
-	case <-s.quit:
-	}
+	_ = s.evch.post(ev, s.quit)
 }
 
 func (s *simscreen) InjectMouse(x, y int, buttons ButtonMask, mod ModMask) {
 	ev := NewEventMouse(x, y, buttons, mod)
+	s.clicks.track(ev)
 	s.postEvent(ev)
 }
 
@@ -342,6 +372,29 @@ func (s *simscreen) InjectKey(key Key, r rune, mod ModMask) {
 	s.postEvent(ev)
 }
 
+func (s *simscreen) InjectPaste(text string) {
+	s.Lock()
+	paste, limit := s.paste, s.pasteMax
+	s.Unlock()
+
+	if paste && limit > 0 {
+		s.postEvent(NewEventPasteText(text, limit))
+		return
+	}
+	if paste {
+		s.postEvent(NewEventPaste(true))
+	}
+	for _, r := range text {
+		if r == '\n' {
+			r = '\r'
+		}
+		s.postEvent(NewEventKey(KeyRune, r, ModNone))
+	}
+	if paste {
+		s.postEvent(NewEventPaste(false))
+	}
+}
+
 func (s *simscreen) InjectKeyBytes(b []byte) bool {
 	failed := false
 
@@ -496,7 +549,7 @@ func (s *simscreen) GetCells() *CellBuffer {
 	return &s.back
 }
 
-func (s *simscreen) EventQ() chan Event {
+func (s *simscreen) EventQ() *eventQueue {
 	return s.evch
 }
 
@@ -512,10 +565,103 @@ func (s *simscreen) GetTitle() string {
 	return s.title
 }
 
+func (s *simscreen) SetMouseShape(shape MouseShape) {
+	s.Lock()
+	s.shape = shape
+	s.Unlock()
+}
+
+func (s *simscreen) GetMouseShape() MouseShape {
+	s.Lock()
+	defer s.Unlock()
+	return s.shape
+}
+
 func (s *simscreen) SetClipboard(data []byte) {
 	s.clipboard = data
 }
 
+func (s *simscreen) PrintAbove(...string) {}
+
+func (s *simscreen) DrawImage(int, int, int, int, image.Image) bool {
+	return false
+}
+
+func (s *simscreen) LoadImage(image.Image) Image {
+	return nil
+}
+
+func (s *simscreen) GetColorScheme() {}
+
+func (s *simscreen) EnableColorScheme() {}
+
+func (s *simscreen) DisableColorScheme() {}
+
+func (s *simscreen) SetPaletteColor(index int, c Color) {
+	s.Lock()
+	if s.palette == nil {
+		s.palette = make(map[int]Color)
+	}
+	s.palette[index] = c.TrueColor()
+	s.Unlock()
+}
+
+func (s *simscreen) ResetPalette() {
+	s.Lock()
+	s.palette = nil
+	s.Unlock()
+}
+
+func (s *simscreen) QueryPaletteColor(index int) {
+	if index < 0 || index > 255 {
+		return
+	}
+	s.Lock()
+	c, ok := s.palette[index]
+	s.Unlock()
+	if !ok {
+		c = PaletteColor(index).TrueColor()
+	}
+	s.postEvent(NewEventPaletteColor(index, c))
+}
+
+func (s *simscreen) QueryCursorPosition() {
+	x, y, _ := s.GetCursorPosition(0)
+	s.postEvent(NewEventCursorPosition(x, y))
+}
+
+func (s *simscreen) GetCursorPosition(time.Duration) (int, int, bool) {
+	s.Lock()
+	defer s.Unlock()
+	return max(s.cursorx, 0), max(s.cursory, 0), true
+}
+
+func (s *simscreen) Query(context.Context, string, ReplyMatcher) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *simscreen) PostQuery(string, ReplyMatcher) {
+}
+
+func (s *simscreen) Capabilities() Capabilities {
+	return Capabilities{
+		TrueColor:      true,
+		Focus:          true,
+		BracketedPaste: true,
+		Name:           "simulation",
+	}
+}
+
+func (s *simscreen) ProbeCapabilities(time.Duration) Capabilities {
+	return s.Capabilities()
+}
+
+func (s *simscreen) ProbeWidths(time.Duration) WidthPolicy {
+	s.Lock()
+	defer s.Unlock()
+	return s.back.GetWidthPolicy()
+}
+
 func (s *simscreen) GetClipboard() {
 	if s.clipboard != nil {
 		ev := NewEventClipboard(s.clipboard)
@@ -523,6 +669,15 @@ func (s *simscreen) GetClipboard() {
 	}
 }
 
+func (s *simscreen) GetClipboardContext(ctx context.Context) ([]byte, error) {
+	if s.clipboard != nil {
+		return append([]byte{}, s.clipboard...), nil
+	}
+	// like a terminal that ignores the request
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
 func (s *simscreen) GetClipboardData() []byte {
 	return s.clipboard
 }
@@ -0,0 +1,149 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+	"image"
+	imgpalette "image/color/palette"
+	"image/draw"
+	"strconv"
+)
+
+// scaleImage scales an image to fit within the given size in pixels,
+// preserving its aspect ratio.  Nearest neighbor sampling is used, which
+// is crude, but fast and good enough at the sizes of terminal cells.
+func scaleImage(img image.Image, width, height int) *image.NRGBA {
+	b := img.Bounds()
+	iw, ih := b.Dx(), b.Dy()
+	if iw <= 0 || ih <= 0 || width <= 0 || height <= 0 {
+		return nil
+	}
+	tw, th := width, ih*width/iw
+	if th > height {
+		tw, th = iw*height/ih, height
+	}
+	tw, th = max(tw, 1), max(th, 1)
+	dst := image.NewNRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		sy := b.Min.Y + y*ih/th
+		for x := 0; x < tw; x++ {
+			dst.Set(x, y, img.At(b.Min.X+x*iw/tw, sy))
+		}
+	}
+	return dst
+}
+
+// encodeSixel encodes an image as a sixel sequence, scaled to fit within
+// the given size in pixels.  The colors are reduced to a fixed palette of
+// 256 colors with dithering.  Transparent pixels are left untouched.
+func encodeSixel(img image.Image, width, height int) []byte {
+	src := scaleImage(img, width, height)
+	if src == nil {
+		return nil
+	}
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	pal := image.NewPaletted(bounds, imgpalette.Plan9)
+	draw.FloydSteinberg.Draw(pal, bounds, src, image.Point{})
+
+	var buf bytes.Buffer
+	num := func(v int) {
+		buf.WriteString(strconv.Itoa(v))
+	}
+	// P2 of 1 means pixels we don't set are left alone (transparent)
+	buf.WriteString("\x1bP0;1;0q\"1;1;")
+	num(w)
+	buf.WriteByte(';')
+	num(h)
+
+	var used [256]bool
+	for _, i := range pal.Pix {
+		used[i] = true
+	}
+	for i, c := range pal.Palette {
+		if !used[i] {
+			continue
+		}
+		// sixel colors are expressed in percent
+		r, g, b, _ := c.RGBA()
+		buf.WriteByte('#')
+		num(i)
+		buf.WriteString(";2;")
+		num(int(r * 100 / 0xffff))
+		buf.WriteByte(';')
+		num(int(g * 100 / 0xffff))
+		buf.WriteByte(';')
+		num(int(b * 100 / 0xffff))
+	}
+
+	row := make([]byte, w)
+	for y := 0; y < h; y += 6 {
+		if y > 0 {
+			buf.WriteByte('-') // next band
+		}
+		var band [256]bool
+		for k := 0; k < 6 && y+k < h; k++ {
+			for _, i := range pal.Pix[(y+k)*pal.Stride : (y+k)*pal.Stride+w] {
+				band[i] = true
+			}
+		}
+		first := true
+		for ci := range band {
+			if !band[ci] {
+				continue
+			}
+			for x := 0; x < w; x++ {
+				bits := byte(0)
+				for k := 0; k < 6 && y+k < h; k++ {
+					if pal.Pix[(y+k)*pal.Stride+x] == uint8(ci) && src.Pix[(y+k)*src.Stride+x*4+3] >= 128 {
+						bits |= 1 << k
+					}
+				}
+				row[x] = '?' + bits
+			}
+			// nothing to draw for this color (all transparent)
+			end := len(row)
+			for end > 0 && row[end-1] == '?' {
+				end--
+			}
+			if end == 0 {
+				continue
+			}
+			if !first {
+				buf.WriteByte('$') // back to the start of the band
+			}
+			first = false
+			buf.WriteByte('#')
+			num(ci)
+			for x := 0; x < end; {
+				n := 1
+				for x+n < end && row[x+n] == row[x] {
+					n++
+				}
+				if n > 3 {
+					buf.WriteByte('!')
+					num(n)
+					buf.WriteByte(row[x])
+				} else {
+					buf.Write(row[x : x+n])
+				}
+				x += n
+			}
+		}
+	}
+	buf.WriteString("\x1b\\")
+	return buf.Bytes()
+}
@@ -36,6 +36,15 @@ func init() {
 		SetCursor:         "\x1b[%i%p1%d;%p2%dH",
 		CursorBack1:       "\b",
 		CursorUp1:         "\x1b[A",
+		CursorUpN:         "\x1b[%p1%dA",
+		CursorDownN:       "\x1b[%p1%dB",
+		CursorForwardN:    "\x1b[%p1%dC",
+		SetScrollRegion:   "\x1b[%i%p1%d;%p2%dr",
+		ScrollForward:     "\x1bD",
+		ScrollReverse:     "\x1bM",
+		ClearToEOL:        "\x1b[K",
+		ClearToEOS:        "\x1b[J",
+		EraseChars:        "\x1b[%p1%dX",
 		KeyUp:             "\x1b[A",
 		KeyDown:           "\x1b[B",
 		KeyRight:          "\x1b[C",
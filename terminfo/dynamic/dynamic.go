@@ -117,7 +117,7 @@ func unescape(s string) string {
 }
 
 func (tc *termcap) setupterm(name string) error {
-	cmd := exec.Command("infocmp", "-1", name)
+	cmd := exec.Command("infocmp", "-x", "-1", name)
 	output := &bytes.Buffer{}
 	cmd.Stdout = output
 
@@ -213,6 +213,19 @@ func LoadTerminfo(name string) (*terminfo.Terminfo, string, error) {
 	t.SetCursor = tc.getstr("cup")
 	t.CursorBack1 = tc.getstr("cub1")
 	t.CursorUp1 = tc.getstr("cuu1")
+	t.CursorUpN = tc.getstr("cuu")
+	t.CursorDownN = tc.getstr("cud")
+	t.CursorForwardN = tc.getstr("cuf")
+	t.SetScrollRegion = tc.getstr("csr")
+	t.ScrollForward = tc.getstr("ind")
+	t.ScrollReverse = tc.getstr("ri")
+	t.ScrollForwardN = tc.getstr("indn")
+	t.ScrollReverseN = tc.getstr("rin")
+	t.ClearToEOL = tc.getstr("el")
+	t.ClearToEOS = tc.getstr("ed")
+	t.EraseChars = tc.getstr("ech")
+	t.RepeatChar = tc.getstr("rep")
+	t.BackColorErase = tc.getflag("bce")
 	t.KeyF1 = tc.getstr("kf1")
 	t.KeyF2 = tc.getstr("kf2")
 	t.KeyF3 = tc.getstr("kf3")
@@ -419,5 +432,11 @@ func LoadTerminfo(name string) (*terminfo.Terminfo, string, error) {
 		t.SetFgBg = fg + ";" + bg
 	}
 
+	// Sync is an extended capability, for synchronized output.
+	if sync := tc.getstr("Sync"); sync != "" {
+		t.EnterSync = t.TParm(sync, 1)
+		t.ExitSync = t.TParm(sync, 2)
+	}
+
 	return t, tc.desc, nil
 }
@@ -41,6 +41,15 @@ func init() {
 		SetCursor:         "\x1b[%i%p1%d;%p2%dH",
 		CursorBack1:       "\b",
 		CursorUp1:         "\x1b[A",
+		CursorUpN:         "\x1b[%p1%dA",
+		CursorDownN:       "\x1b[%p1%dB",
+		CursorForwardN:    "\x1b[%p1%dC",
+		SetScrollRegion:   "\x1b[%i%p1%d;%p2%dr",
+		ScrollForward:     "\n",
+		ScrollReverse:     "\x1bM",
+		ClearToEOL:        "\x1b[K",
+		ClearToEOS:        "\x1b[J",
+		EraseChars:        "\x1b[%p1%dX",
 		KeyUp:             "\x1bOA",
 		KeyDown:           "\x1bOB",
 		KeyRight:          "\x1bOC",
@@ -67,6 +76,7 @@ func init() {
 		KeyBacktab:        "\x1b[Z",
 		Modifiers:         1,
 		AutoMargin:        true,
+		BackColorErase:    true,
 		XTermLike:         true,
 	})
 
@@ -105,6 +115,15 @@ func init() {
 		SetCursor:         "\x1b[%i%p1%d;%p2%dH",
 		CursorBack1:       "\b",
 		CursorUp1:         "\x1b[A",
+		CursorUpN:         "\x1b[%p1%dA",
+		CursorDownN:       "\x1b[%p1%dB",
+		CursorForwardN:    "\x1b[%p1%dC",
+		SetScrollRegion:   "\x1b[%i%p1%d;%p2%dr",
+		ScrollForward:     "\n",
+		ScrollReverse:     "\x1bM",
+		ClearToEOL:        "\x1b[K",
+		ClearToEOS:        "\x1b[J",
+		EraseChars:        "\x1b[%p1%dX",
 		KeyUp:             "\x1bOA",
 		KeyDown:           "\x1bOB",
 		KeyRight:          "\x1bOC",
@@ -131,6 +150,7 @@ func init() {
 		KeyBacktab:        "\x1b[Z",
 		Modifiers:         1,
 		AutoMargin:        true,
+		BackColorErase:    true,
 		XTermLike:         true,
 	})
 }
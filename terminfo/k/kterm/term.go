@@ -36,6 +36,14 @@ func init() {
 		SetCursor:         "\x1b[%i%p1%d;%p2%dH",
 		CursorBack1:       "\b",
 		CursorUp1:         "\x1b[A",
+		CursorUpN:         "\x1b[%p1%dA",
+		CursorDownN:       "\x1b[%p1%dB",
+		CursorForwardN:    "\x1b[%p1%dC",
+		SetScrollRegion:   "\x1b[%i%p1%d;%p2%dr",
+		ScrollForward:     "\n",
+		ScrollReverse:     "\x1bM",
+		ClearToEOL:        "\x1b[K",
+		ClearToEOS:        "\x1b[J",
 		KeyUp:             "\x1bOA",
 		KeyDown:           "\x1bOB",
 		KeyRight:          "\x1bOC",
@@ -239,6 +239,19 @@ func getinfo(name string) (*terminfo.Terminfo, string, error) {
 	t.SetCursor = tc.getstr("cup")
 	t.CursorBack1 = tc.getstr("cub1")
 	t.CursorUp1 = tc.getstr("cuu1")
+	t.CursorUpN = tc.getstr("cuu")
+	t.CursorDownN = tc.getstr("cud")
+	t.CursorForwardN = tc.getstr("cuf")
+	t.SetScrollRegion = tc.getstr("csr")
+	t.ScrollForward = tc.getstr("ind")
+	t.ScrollReverse = tc.getstr("ri")
+	t.ScrollForwardN = tc.getstr("indn")
+	t.ScrollReverseN = tc.getstr("rin")
+	t.ClearToEOL = tc.getstr("el")
+	t.ClearToEOS = tc.getstr("ed")
+	t.EraseChars = tc.getstr("ech")
+	t.RepeatChar = tc.getstr("rep")
+	t.BackColorErase = tc.getflag("bce")
 	t.InsertChar = tc.getstr("ich1")
 	t.AutoMargin = tc.getflag("am")
 	t.KeyF1 = tc.getstr("kf1")
@@ -433,6 +446,10 @@ func getinfo(name string) (*terminfo.Terminfo, string, error) {
 			t.DashedUnderline = t.TParm(smulx, 5)
 		}
 	}
+	if sync := tc.getstr("Sync"); sync != "" {
+		t.EnterSync = t.TParm(sync, 1)
+		t.ExitSync = t.TParm(sync, 2)
+	}
 	return t, tc.desc, nil
 }
 
@@ -530,6 +547,18 @@ func dotGoInfo(w io.Writer, terms []*TData) {
 		dotGoAddStr(w, "SetCursor", t.SetCursor)
 		dotGoAddStr(w, "CursorBack1", t.CursorBack1)
 		dotGoAddStr(w, "CursorUp1", t.CursorUp1)
+		dotGoAddStr(w, "CursorUpN", t.CursorUpN)
+		dotGoAddStr(w, "CursorDownN", t.CursorDownN)
+		dotGoAddStr(w, "CursorForwardN", t.CursorForwardN)
+		dotGoAddStr(w, "SetScrollRegion", t.SetScrollRegion)
+		dotGoAddStr(w, "ScrollForward", t.ScrollForward)
+		dotGoAddStr(w, "ScrollReverse", t.ScrollReverse)
+		dotGoAddStr(w, "ScrollForwardN", t.ScrollForwardN)
+		dotGoAddStr(w, "ScrollReverseN", t.ScrollReverseN)
+		dotGoAddStr(w, "ClearToEOL", t.ClearToEOL)
+		dotGoAddStr(w, "ClearToEOS", t.ClearToEOS)
+		dotGoAddStr(w, "EraseChars", t.EraseChars)
+		dotGoAddStr(w, "RepeatChar", t.RepeatChar)
 		dotGoAddStr(w, "KeyUp", t.KeyUp)
 		dotGoAddStr(w, "KeyDown", t.KeyDown)
 		dotGoAddStr(w, "KeyRight", t.KeyRight)
@@ -630,6 +659,7 @@ func dotGoInfo(w io.Writer, terms []*TData) {
 		dotGoAddInt(w, "Modifiers", t.Modifiers)
 		dotGoAddFlag(w, "TrueColor", t.TrueColor)
 		dotGoAddFlag(w, "AutoMargin", t.AutoMargin)
+		dotGoAddFlag(w, "BackColorErase", t.BackColorErase)
 		dotGoAddStr(w, "InsertChar", t.InsertChar)
 		dotGoAddStr(w, "CursorDefault", t.CursorDefault)
 		dotGoAddStr(w, "CursorBlinkingBlock", t.CursorBlinkingBlock)
@@ -642,6 +672,8 @@ func dotGoInfo(w io.Writer, terms []*TData) {
 		dotGoAddStr(w, "CurlyUnderline", t.CurlyUnderline)
 		dotGoAddStr(w, "DottedUnderline", t.DottedUnderline)
 		dotGoAddStr(w, "DashedUnderline", t.DashedUnderline)
+		dotGoAddStr(w, "EnterSync", t.EnterSync)
+		dotGoAddStr(w, "ExitSync", t.ExitSync)
 		dotGoAddFlag(w, "XTermLike", t.XTermLike)
 		fmt.Fprintln(w, "\t})")
 	}
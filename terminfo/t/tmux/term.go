@@ -41,6 +41,16 @@ func init() {
 		SetCursor:       "\x1b[%i%p1%d;%p2%dH",
 		CursorBack1:     "\b",
 		CursorUp1:       "\x1bM",
+		CursorUpN:       "\x1b[%p1%dA",
+		CursorDownN:     "\x1b[%p1%dB",
+		CursorForwardN:  "\x1b[%p1%dC",
+		SetScrollRegion: "\x1b[%i%p1%d;%p2%dr",
+		ScrollForward:   "\n",
+		ScrollReverse:   "\x1bM",
+		ScrollForwardN:  "\x1b[%p1%dS",
+		ScrollReverseN:  "\x1b[%p1%dT",
+		ClearToEOL:      "\x1b[K",
+		ClearToEOS:      "\x1b[J",
 		KeyUp:           "\x1bOA",
 		KeyDown:         "\x1bOB",
 		KeyRight:        "\x1bOC",
@@ -108,6 +118,16 @@ func init() {
 		SetCursor:       "\x1b[%i%p1%d;%p2%dH",
 		CursorBack1:     "\b",
 		CursorUp1:       "\x1bM",
+		CursorUpN:       "\x1b[%p1%dA",
+		CursorDownN:     "\x1b[%p1%dB",
+		CursorForwardN:  "\x1b[%p1%dC",
+		SetScrollRegion: "\x1b[%i%p1%d;%p2%dr",
+		ScrollForward:   "\n",
+		ScrollReverse:   "\x1bM",
+		ScrollForwardN:  "\x1b[%p1%dS",
+		ScrollReverseN:  "\x1b[%p1%dT",
+		ClearToEOL:      "\x1b[K",
+		ClearToEOS:      "\x1b[J",
 		KeyUp:           "\x1bOA",
 		KeyDown:         "\x1bOB",
 		KeyRight:        "\x1bOC",
@@ -31,6 +31,14 @@ func init() {
 		SetCursor:         "\x1b[%i%p1%d;%p2%dH$<5>",
 		CursorBack1:       "\b",
 		CursorUp1:         "\x1b[A$<2>",
+		CursorUpN:         "\x1b[%p1%dA",
+		CursorDownN:       "\x1b[%p1%dB",
+		CursorForwardN:    "\x1b[%p1%dC",
+		SetScrollRegion:   "\x1b[%i%p1%d;%p2%dr",
+		ScrollForward:     "\n",
+		ScrollReverse:     "\x1bM$<5>",
+		ClearToEOL:        "\x1b[K$<3>",
+		ClearToEOS:        "\x1b[J$<50>",
 		KeyUp:             "\x1bOA",
 		KeyDown:           "\x1bOB",
 		KeyRight:          "\x1bOC",
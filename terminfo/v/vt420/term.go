@@ -32,6 +32,15 @@ func init() {
 		SetCursor:         "\x1b[%i%p1%d;%p2%dH$<10>",
 		CursorBack1:       "\b",
 		CursorUp1:         "\x1b[A",
+		CursorUpN:         "\x1b[%p1%dA",
+		CursorDownN:       "\x1b[%p1%dB",
+		CursorForwardN:    "\x1b[%p1%dC",
+		SetScrollRegion:   "\x1b[%i%p1%d;%p2%dr",
+		ScrollForward:     "\x1bD",
+		ScrollReverse:     "\x1bM",
+		ClearToEOL:        "\x1b[K$<3>",
+		ClearToEOS:        "\x1b[J$<50>",
+		EraseChars:        "\x1b[%p1%dX",
 		KeyUp:             "\x1b[A",
 		KeyDown:           "\x1b[B",
 		KeyRight:          "\x1b[C",
@@ -32,6 +32,10 @@ func init() {
 		SetCursor:         "\x1b=%p1%' '%+%c%p2%' '%+%c",
 		CursorBack1:       "\b",
 		CursorUp1:         "\v",
+		ScrollForward:     "\n$<5>",
+		ScrollReverse:     "\x1bj$<7>",
+		ClearToEOL:        "\x1bT",
+		ClearToEOS:        "\x1bY$<100>",
 		KeyUp:             "\v",
 		KeyDown:           "\n",
 		KeyRight:          "\f",
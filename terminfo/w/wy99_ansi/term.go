@@ -33,6 +33,15 @@ func init() {
 		SetCursor:         "\x1b[%i%p1%d;%p2%dH",
 		CursorBack1:       "\b$<1>",
 		CursorUp1:         "\x1bM",
+		CursorUpN:         "\x1b[%p1%dA",
+		CursorDownN:       "\x1b[%p1%dB",
+		CursorForwardN:    "\x1b[%p1%dC$<1>",
+		SetScrollRegion:   "\x1b[%i%p1%d;%p2%dr",
+		ScrollForward:     "\n$<1>",
+		ScrollReverse:     "\x1bM",
+		ClearToEOL:        "\x1b[K$<1>",
+		ClearToEOS:        "\x1b[J$<8*>",
+		EraseChars:        "\x1b[%p1%dX",
 		KeyUp:             "\x1bOA",
 		KeyDown:           "\x1bOB",
 		KeyRight:          "\x1bOC",
@@ -89,6 +98,15 @@ func init() {
 		SetCursor:         "\x1b[%i%p1%d;%p2%dH",
 		CursorBack1:       "\b$<1>",
 		CursorUp1:         "\x1bM",
+		CursorUpN:         "\x1b[%p1%dA",
+		CursorDownN:       "\x1b[%p1%dB",
+		CursorForwardN:    "\x1b[%p1%dC$<1>",
+		SetScrollRegion:   "\x1b[%i%p1%d;%p2%dr",
+		ScrollForward:     "\n$<1>",
+		ScrollReverse:     "\x1bM",
+		ClearToEOL:        "\x1b[K$<1>",
+		ClearToEOS:        "\x1b[J$<8*>",
+		EraseChars:        "\x1b[%p1%dX",
 		KeyUp:             "\x1bOA",
 		KeyDown:           "\x1bOB",
 		KeyRight:          "\x1bOC",
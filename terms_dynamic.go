@@ -29,6 +29,7 @@ import (
 	"github.com/gdamore/tcell/v2/terminfo/dynamic"
 
 	"fmt"
+	"strings"
 )
 
 func loadDynamicTerminfo(term string) (*terminfo.Terminfo, error) {
@@ -39,5 +40,14 @@ func loadDynamicTerminfo(term string) (*terminfo.Terminfo, error) {
 	if e != nil {
 		return nil, e
 	}
+	if ti.SetScrollRegion == "" && !strings.Contains(ti.Name, "linux") && (ti.Mouse != "" || ti.XTermLike) {
+		// Every terminal that claims to be like XTerm can scroll,
+		// even if the entry on this system predates it.
+		ti.SetScrollRegion = "\x1b[%i%p1%d;%p2%dr"
+		ti.ScrollForward = "\n"
+		ti.ScrollReverse = "\x1bM"
+		ti.ScrollForwardN = "\x1b[%p1%dS"
+		ti.ScrollReverseN = "\x1b[%p1%dT"
+	}
 	return ti, nil
 }
@@ -19,6 +19,7 @@ package tcell
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"io"
@@ -47,6 +48,20 @@ func NewTerminfoScreen() (Screen, error) {
 	return NewTerminfoScreenFromTty(nil)
 }
 
+// NewInlineScreen returns a Screen that, rather than taking over the entire
+// terminal, occupies the given number of lines starting at the current cursor
+// position, scrolling the terminal up to make room if needed.  The alternate
+// screen is not used, so when the screen is finalized its last frame remains
+// on the terminal, and output printed with PrintAbove becomes part of the
+// scrollback.  If the terminal has fewer lines, the entire height is used.
+// This is the same as NewTerminfoScreenWithOptions with WithInline.
+func NewInlineScreen(lines int) (Screen, error) {
+	if lines < 1 {
+		return nil, errors.New("invalid number of lines")
+	}
+	return NewTerminfoScreenWithOptions(nil, nil, WithInline(lines))
+}
+
 // LookupTerminfo attempts to find a definition for the named $TERM falling
 // back to attempting to parse the output from infocmp.
 func LookupTerminfo(name string) (ti *terminfo.Terminfo, e error) {
@@ -70,21 +85,47 @@ func LookupTerminfo(name string) (ti *terminfo.Terminfo, e error) {
 // If passed terminfo is nil, then TERM environment variable is queried for
 // terminal specification.
 func NewTerminfoScreenFromTtyTerminfo(tty Tty, ti *terminfo.Terminfo) (s Screen, e error) {
+	return NewTerminfoScreenWithOptions(tty, ti)
+}
+
+// NewTerminfoScreenWithOptions is like NewTerminfoScreenFromTtyTerminfo,
+// but the Screen is configured with the given options.
+func NewTerminfoScreenWithOptions(tty Tty, ti *terminfo.Terminfo, opts ...ScreenOption) (s Screen, e error) {
 	if ti == nil {
 		ti, e = LookupTerminfo(os.Getenv("TERM"))
 		if e != nil {
 			return
 		}
 	}
+	o := newScreenOptions(opts)
+	if o.trueColor != nil && *o.trueColor &&
+		ti.SetFgBgRGB == "" && ti.SetFgRGB == "" && ti.SetBgRGB == "" {
+		// use a copy, as the database entry is shared
+		nti := *ti
+		nti.SetFgRGB = "\x1b[38;2;%p1%d;%p2%d;%p3%dm"
+		nti.SetBgRGB = "\x1b[48;2;%p1%d;%p2%d;%p3%dm"
+		nti.SetFgBgRGB = "\x1b[38;2;%p1%d;%p2%d;%p3%d;" +
+			"48;2;%p4%d;%p5%d;%p6%dm"
+		ti = &nti
+	}
 
-	t := &tScreen{ti: ti, tty: tty}
+	t := &tScreen{ti: ti, tty: tty, opts: o}
 
 	t.keyexist = make(map[Key]bool)
 	t.keycodes = make(map[string]*tKeyCode)
+	t.eventQ = newEventQueue(o.queueSize)
+	t.mouseFlags = o.mouse
+	t.pasteEnabled = o.paste
+	t.focusEnabled = o.focus
+	t.inline = o.inline
 	if len(ti.Mouse) > 0 {
 		t.mouse = []byte(ti.Mouse)
 	}
 	t.prepareKeys()
+	if t.inline > 0 && (t.clearEos == "" || t.cursorUpN == "" || t.cursorDownN == "" || t.cursorFwdN == "") {
+		// we cannot find our way around without these
+		return nil, ErrNotSupported
+	}
 	t.buildAcsMap()
 	t.resizeQ = make(chan bool, 1)
 	t.fallback = make(map[rune]string)
@@ -103,6 +144,10 @@ func NewTerminfoScreenFromTty(tty Tty) (Screen, error) {
 	return NewTerminfoScreenFromTtyTerminfo(tty, nil)
 }
 
+func newTerminfoScreen(opts []ScreenOption) (Screen, error) {
+	return NewTerminfoScreenWithOptions(nil, nil, opts...)
+}
+
 // tKeyCode represents a combination of a key code and modifiers.
 type tKeyCode struct {
 	key Key
@@ -125,6 +170,8 @@ type tScreen struct {
 	quit         chan struct{}
 	keyexist     map[Key]bool
 	keycodes     map[string]*tKeyCode
+	keyseqs      map[tKeyCode]string
+	keys         *keyTrie
 	keychan      chan []byte
 	keytimer     *time.Timer
 	keyexpire    time.Time
@@ -144,6 +191,7 @@ type tScreen struct {
 	truecolor    bool
 	escaped      bool
 	buttondn     bool
+	clicks       mouseTracker
 	finiOnce     sync.Once
 	enablePaste  string
 	disablePaste string
@@ -166,17 +214,79 @@ type tScreen struct {
 	cursorFg     string
 	saved        *term.State
 	stopQ        chan struct{}
-	eventQ       chan Event
+	eventQ       *eventQueue
 	running      bool
 	wg           sync.WaitGroup
 	mouseFlags   MouseFlags
 	pasteEnabled bool
+	pasteEnd     []byte
+	pasteLimit   int
+	pasting      bool
+	pasteData    []byte
+	pasteExpire  time.Time
 	focusEnabled bool
 	setTitle     string
 	saveTitle    string
 	restoreTitle string
 	title        string
 	setClipboard string
+	setMouse     string
+	mouseShape   MouseShape
+	kittyQuery   string
+	kittyKeys    bool
+	kbdFlags     KeyboardFlags
+	syncQuery    string
+	enterSync    string
+	exitSync     string
+	gcQuery      string
+	gcMode       bool
+	mouseQuery   string
+	mouseEnc     int
+	cellW        int
+	cellH        int
+	setScroll    string
+	scrollUp     string
+	scrollDown   string
+	scrollUpN    string
+	scrollDownN  string
+	clearEol     string
+	eraseChars   string
+	repeatChar   string
+	clearEos     string
+	cursorUpN    string
+	cursorDownN  string
+	cursorFwdN   string
+	inline       int
+	iy           int
+	origin       int
+	above        []string
+	caps         Capabilities
+	modes        map[int]int
+	da1Sent      int
+	da1Seen      int
+	probeWant    int
+	probeDone    chan struct{}
+	probeSent    bool
+	queryColors  string
+	setScheme    string
+	resetScheme  string
+	schemeOn     bool
+	schemeHint   ColorScheme
+	schemeFg     Color
+	setPalette   string
+	resetPalette string
+	queryPalette string
+	paletteMax   int
+	palColors    map[int]Color
+	images       []*tImage
+	gfxQuery     string
+	gfxLast      int
+	gfxImages    []*kittyImage
+	gfxPending   []string
+	placements   []*kittyPlacement
+	cprQuery     string
+	queries      []*tQuery
+	opts         screenOptions
 
 	sync.Mutex
 }
@@ -186,11 +296,15 @@ func (t *tScreen) Init() error {
 		return e
 	}
 
+	t.keys = newKeyTrie(t.keycodes)
 	t.keychan = make(chan []byte, 10)
-	t.keytimer = time.NewTimer(time.Millisecond * 50)
+	t.keytimer = time.NewTimer(t.opts.escTimeout)
 	t.charset = "UTF-8"
 
 	t.charset = getCharset()
+	if GetEncoding(t.charset) == nil && t.opts.charset != "" {
+		t.charset = t.opts.charset
+	}
 	if enc := GetEncoding(t.charset); enc != nil {
 		t.encoder = enc.NewEncoder()
 		t.decoder = enc.NewDecoder()
@@ -216,6 +330,9 @@ func (t *tScreen) Init() error {
 	if os.Getenv("TCELL_TRUECOLOR") == "disable" {
 		t.truecolor = false
 	}
+	if t.opts.trueColor != nil {
+		t.truecolor = *t.opts.trueColor
+	}
 	nColors := t.nColors()
 	if nColors > 256 {
 		nColors = 256 // clip to reasonable limits
@@ -229,7 +346,6 @@ func (t *tScreen) Init() error {
 	}
 
 	t.quit = make(chan struct{})
-	t.eventQ = make(chan Event, 10)
 
 	t.Lock()
 	t.cx = -1
@@ -254,6 +370,7 @@ func (t *tScreen) prepareKeyMod(key Key, mod ModMask, val string) {
 		if _, exist := t.keycodes[val]; !exist {
 			t.keyexist[key] = true
 			t.keycodes[val] = &tKeyCode{key: key, mod: mod}
+			t.addKeySeq(key, mod, val)
 		}
 	}
 }
@@ -262,8 +379,22 @@ func (t *tScreen) prepareKeyModReplace(key Key, replace Key, mod ModMask, val st
 	if val != "" {
 		// Do not override codes that already exist
 		if old, exist := t.keycodes[val]; !exist || old.key == replace {
+			if exist && t.keyseqs[*old] == val {
+				delete(t.keyseqs, *old)
+			}
 			t.keyexist[key] = true
 			t.keycodes[val] = &tKeyCode{key: key, mod: mod}
+			t.addKeySeq(key, mod, val)
+		}
+	}
+}
+
+// addKeySeq records the sequence for the key, if we are keeping track of
+// them (for an Encoder), and it is the first one for the key.
+func (t *tScreen) addKeySeq(key Key, mod ModMask, val string) {
+	if t.keyseqs != nil {
+		if _, exist := t.keyseqs[tKeyCode{key: key, mod: mod}]; !exist {
+			t.keyseqs[tKeyCode{key: key, mod: mod}] = val
 		}
 	}
 }
@@ -351,11 +482,13 @@ func (t *tScreen) prepareBracketedPaste() {
 		t.disablePaste = t.ti.DisablePaste
 		t.prepareKey(keyPasteStart, t.ti.PasteStart)
 		t.prepareKey(keyPasteEnd, t.ti.PasteEnd)
+		t.pasteEnd = []byte(t.ti.PasteEnd)
 	} else if t.ti.Mouse != "" || t.ti.XTermLike {
 		t.enablePaste = "\x1b[?2004h"
 		t.disablePaste = "\x1b[?2004l"
 		t.prepareKey(keyPasteStart, "\x1b[200~")
 		t.prepareKey(keyPasteEnd, "\x1b[201~")
+		t.pasteEnd = []byte("\x1b[201~")
 	}
 }
 
@@ -457,6 +590,199 @@ func (t *tScreen) prepareExtendedOSC() {
 		// sent string, when we support that.
 		t.setClipboard = "\x1b]52;c;%p1%s\x1b\\"
 	}
+
+	// OSC 22 sets the shape of the mouse pointer, which kitty, foot,
+	// xterm and others understand (using the CSS names).  Terminals that
+	// merely support the mouse, such as screen, might show it instead.
+	if t.ti.XTermLike {
+		t.setMouse = "\x1b]22;%p1%s\x1b\\"
+	}
+}
+
+func (t *tScreen) prepareSyncOutput() {
+	// Synchronized output (DEC private mode 2026) asks the terminal to hold
+	// off painting until a frame is complete, which avoids tearing when the
+	// write of a frame is split up on its way to the terminal.  A user who
+	// finds their terminal misbehaves with it can disable it.
+	if sync := t.opts.syncOutput; sync != nil {
+		if !*sync {
+			return
+		}
+		if t.ti.EnterSync == "" {
+			// the application knows better than to ask
+			t.enterSync = "\x1b[?2026h"
+			t.exitSync = "\x1b[?2026l"
+			return
+		}
+	} else if os.Getenv("TCELL_SYNC") == "disable" {
+		return
+	}
+	if t.ti.EnterSync != "" {
+		t.enterSync = t.ti.EnterSync
+		t.exitSync = t.ti.ExitSync
+	} else if strings.Contains(t.ti.Name, "linux") {
+		return
+	} else if t.ti.Mouse != "" || t.ti.XTermLike {
+		// We can't just assume support, as terminals that don't know
+		// about it might not swallow it.  So ask, using DECRQM.
+		t.syncQuery = "\x1b[?2026$p"
+	}
+}
+
+func (t *tScreen) prepareGraphemes() {
+	// Terminals supporting grapheme cluster mode (mode 2027) lay out
+	// text by grapheme clusters, as we do, rather than rune by rune.
+	// So if the terminal has it, we turn it on.  (Wide clusters are
+	// drawn safely either way, as we position the cursor after them.)
+	if os.Getenv("TCELL_GRAPHEMES") == "disable" {
+		return
+	}
+	if strings.Contains(t.ti.Name, "linux") {
+		return
+	}
+	if t.ti.Mouse != "" || t.ti.XTermLike {
+		t.gcQuery = "\x1b[?2027$p"
+	}
+}
+
+func (t *tScreen) prepareMouseEncoding() {
+	// Terminals may support any of several encodings for mouse reports,
+	// which they tell us about in reply to mode queries.  Until they do,
+	// we assume SGR (1006), which nearly all of them understand.
+	if t.ti.Mouse != "" && !strings.Contains(t.ti.Name, "linux") {
+		t.mouseQuery = "\x1b[?1016$p\x1b[?1006$p\x1b[?1015$p\x1b[?1005$p"
+	}
+}
+
+func (t *tScreen) prepareCursorReport() {
+	// Cursor position reports (DSR 6n) date back to the VT100, and
+	// every terminal emulating one supports them.
+	if t.ti.Mouse != "" || t.ti.XTermLike || strings.Contains(t.ti.Name, "linux") {
+		t.cprQuery = "\x1b[6n"
+	}
+}
+
+func (t *tScreen) prepareScrolling() {
+	ti := t.ti
+	t.setScroll = ti.SetScrollRegion
+	t.scrollUp = ti.ScrollForward
+	t.scrollDown = ti.ScrollReverse
+	t.scrollUpN = ti.ScrollForwardN
+	t.scrollDownN = ti.ScrollReverseN
+}
+
+func (t *tScreen) prepareErase() {
+	ti := t.ti
+	t.clearEol = ti.ClearToEOL
+	t.eraseChars = ti.EraseChars
+	t.repeatChar = ti.RepeatChar
+	if ti.Mouse != "" || ti.XTermLike {
+		// Every terminal that claims to be like XTerm has these, even
+		// if its terminfo entry predates them.  (Not so for REP, which
+		// we only use if the entry says it is there.)
+		if t.clearEol == "" {
+			t.clearEol = "\x1b[K"
+		}
+		if t.eraseChars == "" {
+			t.eraseChars = "\x1b[%p1%dX"
+		}
+	}
+}
+
+func (t *tScreen) prepareInline() {
+	// Inline screens don't know where they are on the terminal, so they
+	// move the cursor relative to where it is, and erase only from the
+	// top of the screen down.
+	ti := t.ti
+	t.clearEos = ti.ClearToEOS
+	t.cursorUpN = ti.CursorUpN
+	t.cursorDownN = ti.CursorDownN
+	t.cursorFwdN = ti.CursorForwardN
+	if ti.Mouse != "" || ti.XTermLike {
+		if t.clearEos == "" {
+			t.clearEos = "\x1b[J"
+		}
+		if t.cursorUpN == "" {
+			t.cursorUpN = "\x1b[%p1%dA"
+		}
+		if t.cursorDownN == "" {
+			t.cursorDownN = "\x1b[%p1%dB"
+		}
+		if t.cursorFwdN == "" {
+			t.cursorFwdN = "\x1b[%p1%dC"
+		}
+	}
+}
+
+func (t *tScreen) prepareColorScheme() {
+	// OSC 10 and 11 are widely supported by XTerm-alikes.  Mode 2031
+	// (color scheme update notifications) is newer, but terminals that
+	// don't know about it will just ignore it.
+	if strings.Contains(t.ti.Name, "linux") {
+		return
+	}
+	if t.ti.Mouse != "" || t.ti.XTermLike {
+		t.queryColors = "\x1b]10;?\x1b\\\x1b]11;?\x1b\\"
+		t.setScheme = "\x1b[?2031h"
+		t.resetScheme = "\x1b[?2031l"
+	}
+}
+
+func (t *tScreen) preparePalette() {
+	if strings.Contains(t.ti.Name, "linux") {
+		// The Linux console has its own sequence, limited to 16 colors,
+		// and cannot report them.
+		t.setPalette = "\x1b]P%p1%x%p2%02x%p3%02x%p4%02x"
+		t.resetPalette = "\x1b]R"
+		t.paletteMax = 16
+	} else if t.ti.Mouse != "" || t.ti.XTermLike {
+		t.setPalette = "\x1b]4;%p1%d;rgb:%p2%02x/%p3%02x/%p4%02x\x1b\\"
+		t.resetPalette = "\x1b]104\x1b\\"
+		t.queryPalette = "\x1b]4;%p1%d;?\x1b\\"
+		t.paletteMax = 256
+	}
+}
+
+func (t *tScreen) prepareKittyKeyboard() {
+	// The kitty keyboard protocol lets us tell apart keys that legacy
+	// encodings conflate (Ctrl-I and Tab, or a lone ESC), and can report
+	// additional modifiers along with key repeat and release.  It has to be
+	// negotiated, so we ask for the current flags, followed by a request for
+	// the primary device attributes, which essentially every terminal answers.
+	// If the latter reply arrives without the former, the terminal lacks
+	// support and we just carry on with the legacy encodings.
+	if strings.Contains(t.ti.Name, "linux") {
+		return
+	}
+	if t.ti.Mouse != "" || t.ti.XTermLike {
+		t.kittyQuery = "\x1b[?u\x1b[c"
+	}
+}
+
+func (t *tScreen) prepareKittyGraphics() {
+	// Support for the kitty graphics protocol is detected by sending a
+	// query for a tiny image, which only terminals supporting it answer.
+	// Like the keyboard query, the device attributes query that follows
+	// it tells us when to stop waiting.  (Terminals are expected to
+	// ignore application program commands they do not understand.)
+	if strings.Contains(t.ti.Name, "linux") {
+		return
+	}
+	if t.ti.Mouse != "" || t.ti.XTermLike {
+		t.gfxQuery = "\x1b_Gi=31,s=1,v=1,a=q,t=d,f=24;AAAA\x1b\\"
+	}
+}
+
+// kittyFlags returns the progressive enhancement flags that we want
+// from a terminal supporting the kitty keyboard protocol.
+func (t *tScreen) kittyFlags() int {
+	flags := 1 // disambiguate escape codes
+	if t.kbdFlags != 0 {
+		// Report event types, and all keys as escape codes (so that text
+		// keys report releases too), with their associated text.
+		flags |= 2 | 8 | 16
+	}
+	return flags
 }
 
 func (t *tScreen) prepareCursorStyles() {
@@ -648,6 +974,17 @@ func (t *tScreen) prepareKeys() {
 	t.prepareCursorStyles()
 	t.prepareUnderlines()
 	t.prepareExtendedOSC()
+	t.prepareSyncOutput()
+	t.prepareGraphemes()
+	t.prepareMouseEncoding()
+	t.prepareCursorReport()
+	t.prepareScrolling()
+	t.prepareErase()
+	t.prepareInline()
+	t.prepareColorScheme()
+	t.preparePalette()
+	t.prepareKittyKeyboard()
+	t.prepareKittyGraphics()
 
 outer:
 	// Add key mappings for control keys.
@@ -803,38 +1140,10 @@ func (t *tScreen) sendFgBg(fg Color, bg Color, attr AttrMask) AttrMask {
 	return attr
 }
 
-func (t *tScreen) drawCell(x, y int) int {
-
+// sendStyle changes the current attributes and colors to the given style,
+// if they are not already in effect.
+func (t *tScreen) sendStyle(style Style) {
 	ti := t.ti
-
-	mainc, combc, style, width := t.cells.GetContent(x, y)
-	if !t.cells.Dirty(x, y) {
-		return width
-	}
-
-	if y == t.h-1 && x == t.w-1 && t.ti.AutoMargin && ti.DisableAutoMargin == "" && ti.InsertChar != "" {
-		// our solution is somewhat goofy.
-		// we write to the second to the last cell what we want in the last cell, then we
-		// insert a character at that 2nd to last position to shift the last column into
-		// place, then we rewrite that 2nd to last cell.  Old terminals suck.
-		t.TPuts(ti.TGoto(x-1, y))
-		defer func() {
-			t.TPuts(ti.TGoto(x-1, y))
-			t.TPuts(ti.InsertChar)
-			t.cy = y
-			t.cx = x - 1
-			t.cells.SetDirty(x-1, y, true)
-			_ = t.drawCell(x-1, y)
-			t.TPuts(t.ti.TGoto(0, 0))
-			t.cy = 0
-			t.cx = 0
-		}()
-	} else if t.cy != y || t.cx != x {
-		t.TPuts(ti.TGoto(x, y))
-		t.cx = x
-		t.cy = y
-	}
-
 	if style == StyleDefault {
 		style = t.style
 	}
@@ -908,6 +1217,147 @@ func (t *tScreen) drawCell(x, y int) int {
 
 		t.curstyle = style
 	}
+}
+
+// canErase reports whether cells with the given style look the same as
+// cells erased by the terminal while that style is in effect.
+func (t *tScreen) canErase(style Style) bool {
+	if style == StyleDefault {
+		style = t.style
+	}
+	if style.attrs&(AttrReverse|AttrStrikeThrough) != 0 || style.ulStyle != UnderlineStyleNone || style.url != "" {
+		return false
+	}
+	if t.ti.Colors == 0 {
+		return !style.fg.Valid()
+	}
+	if style.bg != ColorDefault && style.bg != ColorReset && !t.ti.BackColorErase {
+		return false
+	}
+	return true
+}
+
+// drawRun draws a run of identical cells starting at x, using the terminal's
+// erase or repeat capabilities when those are cheaper than sending each cell.
+// It returns the number of cells drawn, which is zero if the cell at x is
+// better left to drawCell.
+func (t *tScreen) drawRun(x, y int) int {
+	ti := t.ti
+	if !t.cells.Dirty(x, y) {
+		return 0
+	}
+	mainc, combc, style, width := t.cells.GetContent(x, y)
+	if width != 1 || len(combc) != 0 {
+		return 0
+	}
+
+	// n is the length of the run, d the number of cells in it that
+	// need drawing.  Clean cells may be erased again, but we don't
+	// want to send them.
+	n, d := 1, 1
+	for x+n < t.w {
+		m, c, st, w := t.cells.GetContent(x+n, y)
+		if m != mainc || len(c) != 0 || st != style || w != 1 || t.cells.locked(x+n, y) {
+			break
+		}
+		if d == n && t.cells.Dirty(x+n, y) {
+			d++
+		}
+		n++
+	}
+	if y == t.h-1 && x+d == t.w {
+		// leave the last cell to drawCell, which knows how to
+		// deal with automatic margins
+		d--
+	}
+	if n == 1 || d < 1 || (d == 1 && mainc != ' ') {
+		return 0
+	}
+
+	enc := t.encodeRune(mainc, nil)
+	best, how := d*len(enc), 0
+	if d > 1 && t.repeatChar != "" && len(enc) == 1 && rune(enc[0]) == mainc && mainc > ' ' && mainc < 0x7f {
+		if c := len(ti.TParm(t.repeatChar, int(mainc), d)); c < best {
+			best, how = c, 1
+		}
+	}
+	if mainc == ' ' && t.canErase(style) {
+		if t.eraseChars != "" {
+			c := len(ti.TParm(t.eraseChars, n))
+			if x+n < t.w {
+				c += len(ti.TGoto(x+n, y))
+			}
+			if c < best {
+				best, how = c, 2
+			}
+		}
+		if x+n == t.w && t.clearEol != "" && len(t.clearEol) < best {
+			best, how = len(t.clearEol), 3
+		}
+	}
+	if how == 0 && d == 1 {
+		return 0
+	}
+
+	if t.cy != y || t.cx != x {
+		t.gotoXY(x, y)
+		t.cx = x
+		t.cy = y
+	}
+	t.sendStyle(style)
+	switch how {
+	case 0:
+		t.writeString(strings.Repeat(string(enc), d))
+		t.cx += d
+	case 1:
+		t.TPuts(ti.TParm(t.repeatChar, int(mainc), d))
+		t.cx += d
+	case 2:
+		t.TPuts(ti.TParm(t.eraseChars, n))
+		d = n
+	case 3:
+		t.TPuts(t.clearEol)
+		d = n
+	}
+	for i := 0; i < d; i++ {
+		t.cells.SetDirty(x+i, y, false)
+	}
+	return d
+}
+
+func (t *tScreen) drawCell(x, y int) int {
+
+	ti := t.ti
+
+	mainc, combc, style, width := t.cells.GetContent(x, y)
+	if !t.cells.Dirty(x, y) {
+		return width
+	}
+
+	if y == t.h-1 && x == t.w-1 && t.ti.AutoMargin && ti.DisableAutoMargin == "" && ti.InsertChar != "" {
+		// our solution is somewhat goofy.
+		// we write to the second to the last cell what we want in the last cell, then we
+		// insert a character at that 2nd to last position to shift the last column into
+		// place, then we rewrite that 2nd to last cell.  Old terminals suck.
+		t.gotoXY(x-1, y)
+		defer func() {
+			t.gotoXY(x-1, y)
+			t.TPuts(ti.InsertChar)
+			t.cy = y
+			t.cx = x - 1
+			t.cells.SetDirty(x-1, y, true)
+			_ = t.drawCell(x-1, y)
+			t.gotoXY(0, 0)
+			t.cy = 0
+			t.cx = 0
+		}()
+	} else if t.cy != y || t.cx != x {
+		t.gotoXY(x, y)
+		t.cx = x
+		t.cy = y
+	}
+
+	t.sendStyle(style)
 
 	// now emit runes - taking care to not overrun width with a
 	// wide character, and to ensure that we emit exactly one regular
@@ -955,6 +1405,16 @@ func (t *tScreen) ShowCursor(x, y int) {
 	t.Unlock()
 }
 
+func (t *tScreen) PrintAbove(lines ...string) {
+	t.Lock()
+	if t.inline > 0 {
+		for _, l := range lines {
+			t.above = append(t.above, strings.Split(l, "\n")...)
+		}
+	}
+	t.Unlock()
+}
+
 func (t *tScreen) SetCursor(cs CursorStyle, cc Color) {
 	t.Lock()
 	t.cursorStyle = cs
@@ -974,7 +1434,7 @@ func (t *tScreen) showCursor() {
 		t.hideCursor()
 		return
 	}
-	t.TPuts(t.ti.TGoto(x, y))
+	t.gotoXY(x, y)
 	t.TPuts(t.ti.ShowCursor)
 	if t.cursorStyles != nil {
 		if esc, ok := t.cursorStyles[t.cursorStyle]; ok {
@@ -1024,14 +1484,120 @@ func (t *tScreen) Show() {
 	t.Unlock()
 }
 
+// beginSync starts a synchronized update, if the terminal supports it.
+func (t *tScreen) beginSync() {
+	if t.enterSync != "" {
+		t.TPuts(t.enterSync)
+	}
+}
+
+// endSync ends a synchronized update, allowing the terminal to paint.
+func (t *tScreen) endSync() {
+	if t.exitSync != "" {
+		t.TPuts(t.exitSync)
+	}
+}
+
 func (t *tScreen) clearScreen() {
 	t.TPuts(t.ti.AttrOff)
 	t.TPuts(t.exitUrl)
 	_ = t.sendFgBg(t.style.fg, t.style.bg, AttrNone)
-	t.TPuts(t.ti.Clear)
+	if t.inline > 0 {
+		// erase just our own lines (and anything below them)
+		t.gotoXY(0, 0)
+		t.TPuts(t.clearEos)
+	} else {
+		t.TPuts(t.ti.Clear)
+	}
 	t.clear = false
 }
 
+// gotoXY moves the cursor.  Inline screens don't know where they are on the
+// terminal, so they move relative to the cursor position, which they track.
+func (t *tScreen) gotoXY(x, y int) {
+	if t.inline == 0 {
+		t.TPuts(t.ti.TGoto(x, y))
+		return
+	}
+	if y >= t.h {
+		y = t.h - 1
+	}
+	t.writeString("\r")
+	if y > t.iy {
+		t.TPuts(t.ti.TParm(t.cursorDownN, y-t.iy))
+	} else if y < t.iy {
+		t.TPuts(t.ti.TParm(t.cursorUpN, t.iy-y))
+	}
+	if x > 0 {
+		t.TPuts(t.ti.TParm(t.cursorFwdN, x))
+	}
+	t.iy = y
+}
+
+// makeRoom ensures that there are enough lines below the cursor for an
+// inline screen, and leaves the cursor at the top left corner of them.
+func (t *tScreen) makeRoom() {
+	t.writeString("\r")
+	if t.h > 1 {
+		t.writeString(strings.Repeat("\n", t.h-1))
+		t.TPuts(t.ti.TParm(t.cursorUpN, t.h-1))
+	}
+	t.iy = 0
+	t.queryOrigin()
+}
+
+// queryOrigin asks the terminal where the top of an inline screen is, as
+// mouse positions are reported relative to the whole terminal.  Until we
+// know, we assume that the screen is at the bottom of the terminal, which
+// is where it ends up if making room scrolled the terminal.
+func (t *tScreen) queryOrigin() {
+	if !t.running || t.cprQuery == "" {
+		return
+	}
+	iy := t.iy
+	t.sendQuery(t.cprQuery, &tQuery{m: cprMatch, expire: time.Now().Add(postQueryTimeout),
+		event: func(data []byte) Event {
+			if _, y, ok := parseCursorReport(data); ok {
+				t.origin = y - iy
+			}
+			return nil
+		}})
+}
+
+// printAbove prints the lines passed to PrintAbove in place of the inline
+// screen, and then makes room for the screen again below them.
+func (t *tScreen) printAbove() {
+	t.gotoXY(0, 0)
+	t.TPuts(t.ti.AttrOff)
+	t.TPuts(t.exitUrl)
+	t.curstyle = styleInvalid
+	t.TPuts(t.clearEos)
+	for _, l := range t.above {
+		var buf []byte
+		for _, r := range l {
+			if r < ' ' || r == 0x7f {
+				continue
+			}
+			buf = append(buf, t.encodeRune(r, nil)...)
+		}
+		t.writeString(string(buf))
+		t.writeString("\r\n")
+	}
+	t.above = nil
+	t.makeRoom()
+	t.cells.Invalidate()
+}
+
+// inlineSize limits the size reported by the terminal to the lines used
+// by an inline screen.
+func (t *tScreen) inlineSize(ws WindowSize) WindowSize {
+	if t.inline > 0 && ws.Height > t.inline {
+		ws.PixelHeight = ws.PixelHeight * t.inline / ws.Height
+		ws.Height = t.inline
+	}
+	return ws
+}
+
 func (t *tScreen) hideCursor() {
 	// does not update cursor position
 	if t.ti.HideCursor != "" {
@@ -1040,8 +1606,62 @@ func (t *tScreen) hideCursor() {
 		// No way to hide cursor, stick it
 		// at bottom right of screen
 		t.cx, t.cy = t.cells.Size()
-		t.TPuts(t.ti.TGoto(t.cx, t.cy))
+		t.gotoXY(t.cx, t.cy)
+	}
+}
+
+// scroll moves rows that are still present, but at a different position,
+// using the terminal's scrolling region, so that only the rows exposed by
+// the scroll need to be drawn.  This makes scrolling text much cheaper.
+func (t *tScreen) scroll() {
+	ti := t.ti
+	if t.scrollUp == "" || t.scrollDown == "" || t.inline > 0 {
+		// scrolling regions use absolute positions
+		return
+	}
+	if len(t.placements) > 0 {
+		// the terminal would move the placements along with the text
+		return
+	}
+	top, bot, n := t.cells.findScroll()
+	if n == 0 {
+		return
+	}
+	region := top != 0 || bot != t.h-1
+	if region {
+		if t.setScroll == "" {
+			return
+		}
+		t.TPuts(ti.TParm(t.setScroll, top, bot))
+	}
+	// the exposed lines may be filled with the current background
+	t.TPuts(ti.AttrOff)
+	t.curstyle = styleInvalid
+	if n > 0 {
+		t.TPuts(ti.TGoto(0, bot))
+		if n > 1 && t.scrollUpN != "" {
+			t.TPuts(ti.TParm(t.scrollUpN, n))
+		} else {
+			for i := 0; i < n; i++ {
+				t.TPuts(t.scrollUp)
+			}
+		}
+	} else {
+		t.TPuts(ti.TGoto(0, top))
+		if n < -1 && t.scrollDownN != "" {
+			t.TPuts(ti.TParm(t.scrollDownN, -n))
+		} else {
+			for i := 0; i < -n; i++ {
+				t.TPuts(t.scrollDown)
+			}
+		}
+	}
+	if region {
+		t.TPuts(ti.TParm(t.setScroll, 0, t.h-1))
 	}
+	t.cx = -1
+	t.cy = -1
+	t.cells.scrollLast(top, bot, n)
 }
 
 func (t *tScreen) draw() {
@@ -1057,16 +1677,32 @@ func (t *tScreen) draw() {
 		t.buffering = false
 	}()
 
+	// hold off painting until the frame is complete
+	t.beginSync()
+
 	// hide the cursor while we move stuff around
 	t.hideCursor()
 
+	if len(t.above) > 0 {
+		t.printAbove()
+	}
 	if t.clear {
 		t.clearScreen()
+		t.hideImages()
+		t.hidePlacements()
+	} else {
+		t.scroll()
 	}
+	t.checkImages()
+	t.lockPlacements()
 
 	for y := 0; y < t.h; y++ {
 		for x := 0; x < t.w; x++ {
-			width := t.drawCell(x, y)
+			if n := t.drawRun(x, y); n > 0 {
+				x += n - 1
+				continue
+			}
+			width := t.drawCell(x, y)
 			if width > 1 {
 				if x+1 < t.w {
 					// this is necessary so that if we ever
@@ -1079,9 +1715,14 @@ func (t *tScreen) draw() {
 		}
 	}
 
+	t.drawImages()
+	t.drawPlacements()
+
 	// restore the cursor
 	t.showCursor()
 
+	t.endSync()
+
 	_, _ = t.buf.WriteTo(t.tty)
 }
 
@@ -1109,6 +1750,10 @@ func (t *tScreen) enableMouse(f MouseFlags) {
 	if len(t.mouse) != 0 {
 		// start by disabling all tracking.
 		t.TPuts("\x1b[?1000l\x1b[?1002l\x1b[?1003l\x1b[?1006l")
+		if t.mouseEnc != 0 && t.mouseEnc != 1006 {
+			t.TPuts("\x1b[?" + strconv.Itoa(t.mouseEnc) + "l")
+		}
+		t.mouseEnc = 0
 		if f&MouseButtonEvents != 0 {
 			t.TPuts("\x1b[?1000h")
 		}
@@ -1119,10 +1764,45 @@ func (t *tScreen) enableMouse(f MouseFlags) {
 			t.TPuts("\x1b[?1003h")
 		}
 		if f&(MouseButtonEvents|MouseDragEvents|MouseMotionEvents) != 0 {
-			t.TPuts("\x1b[?1006h")
+			t.mouseEnc = t.mouseEncoding()
+			if t.mouseEnc != 0 {
+				t.TPuts("\x1b[?" + strconv.Itoa(t.mouseEnc) + "h")
+			}
+		}
+	}
+
+}
+
+// mouseEncoding picks the best encoding for mouse reports that the
+// terminal supports: SGR with pixel coordinates (1016) if we know the
+// size of the cells, then SGR (1006), urxvt (1015), UTF-8 (1005), and
+// finally the legacy X11 encoding (0).
+func (t *tScreen) mouseEncoding() int {
+	if _, ok := t.modes[1006]; !ok {
+		// the terminal did not tell us
+		return 1006
+	}
+	for _, mode := range []int{1016, 1006, 1015, 1005} {
+		if mode == 1016 && (t.cellW == 0 || t.cellH == 0) {
+			continue
 		}
+		if v := t.modes[mode]; v != 0 && v != 4 {
+			return mode
+		}
+	}
+	return 0
+}
+
+// setCellSize notes the size of the cells in pixels, if the terminal
+// reports it, to decode mouse reports that use pixel coordinates.
+func (t *tScreen) setCellSize(ws WindowSize) {
+	if ws.Width != 0 && ws.Height != 0 {
+		t.cellW, t.cellH = ws.CellDimensions()
 	}
+}
 
+func (t *tScreen) SetMouseClickInterval(interval time.Duration) {
+	t.clicks.setInterval(interval)
 }
 
 func (t *tScreen) DisableMouse() {
@@ -1143,6 +1823,16 @@ func (t *tScreen) DisablePaste() {
 	t.Lock()
 	t.pasteEnabled = false
 	t.enablePasting(false)
+	t.abandonPaste()
+	t.Unlock()
+}
+
+func (t *tScreen) SetPasteLimit(limit int) {
+	t.Lock()
+	t.pasteLimit = limit
+	if limit <= 0 {
+		t.abandonPaste()
+	}
 	t.Unlock()
 }
 
@@ -1184,6 +1874,84 @@ func (t *tScreen) disableFocusReporting() {
 	}
 }
 
+func (t *tScreen) GetColorScheme() {
+	t.Lock()
+	if t.queryColors != "" {
+		t.TPuts(t.queryColors)
+	}
+	t.Unlock()
+}
+
+func (t *tScreen) EnableColorScheme() {
+	t.Lock()
+	t.schemeOn = true
+	t.enableColorScheme()
+	t.Unlock()
+}
+
+func (t *tScreen) DisableColorScheme() {
+	t.Lock()
+	t.schemeOn = false
+	t.disableColorScheme()
+	t.Unlock()
+}
+
+func (t *tScreen) enableColorScheme() {
+	if t.setScheme != "" {
+		t.TPuts(t.setScheme)
+	}
+}
+
+func (t *tScreen) disableColorScheme() {
+	if t.resetScheme != "" {
+		t.TPuts(t.resetScheme)
+	}
+}
+
+func (t *tScreen) SetPaletteColor(index int, c Color) {
+	t.Lock()
+	defer t.Unlock()
+	if c = c.TrueColor(); !c.Valid() || index < 0 || index >= t.paletteMax {
+		return
+	}
+	if t.palColors == nil {
+		t.palColors = make(map[int]Color)
+	}
+	t.palColors[index] = c
+	t.sendPalette(index, c)
+}
+
+func (t *tScreen) ResetPalette() {
+	t.Lock()
+	t.palColors = nil
+	if t.resetPalette != "" {
+		t.TPuts(t.resetPalette)
+	}
+	t.Unlock()
+}
+
+func (t *tScreen) QueryPaletteColor(index int) {
+	t.Lock()
+	if t.queryPalette != "" && index >= 0 && index < t.paletteMax {
+		t.TPuts(t.ti.TParm(t.queryPalette, index))
+	}
+	t.Unlock()
+}
+
+func (t *tScreen) sendPalette(index int, c Color) {
+	r, g, b := c.RGB()
+	t.TPuts(t.ti.TParm(t.setPalette, index, int(r), int(g), int(b)))
+}
+
+func (t *tScreen) SetKeyboardFlags(flags KeyboardFlags) {
+	t.Lock()
+	t.kbdFlags = flags
+	if t.kittyKeys {
+		t.TPuts(t.ti.TParm("\x1b[=%p1%d;1u", t.kittyFlags()))
+	}
+	t.Unlock()
+}
+
 func (t *tScreen) Size() (int, int) {
 	t.Lock()
 	w, h := t.w, t.h
@@ -1196,21 +1964,28 @@ func (t *tScreen) resize() {
 	if err != nil {
 		return
 	}
+	t.setCellSize(ws)
+	rows := ws.Height
+	ws = t.inlineSize(ws)
 	if ws.Width == t.w && ws.Height == t.h {
 		return
 	}
 	t.cx = -1
 	t.cy = -1
+	if t.inline > 0 {
+		// the terminal may have rewrapped our lines, and moved them
+		t.clear = true
+		t.origin = max(rows-ws.Height, 0)
+		t.queryOrigin()
+	}
+	t.hideImages()
 
 	t.cells.Resize(ws.Width, ws.Height)
 	t.cells.Invalidate()
 	t.h = ws.Height
 	t.w = ws.Width
 	ev := &EventResize{t: time.Now(), ws: ws}
-	select {
-	case t.eventQ <- ev:
-	default:
-	}
+	_ = t.eventQ.post(ev, nil)
 }
 
 func (t *tScreen) Colors() int {
@@ -1357,10 +2132,13 @@ func (t *tScreen) buildMouseEvent(x, y, btn int) *EventMouse {
 
 	// Some terminals will report mouse coordinates outside the
 	// screen, especially with click-drag events.  Clip the coordinates
-	// to the screen in that case.
-	x, y = t.clip(x, y)
+	// to the screen in that case.  Inline screens get positions relative
+	// to the whole terminal, not just their own lines.
+	x, y = t.clip(x, y-t.origin)
 
-	return NewEventMouse(x, y, button, mod)
+	ev := NewEventMouse(x, y, button, mod)
+	t.clicks.track(ev)
+	return ev
 }
 
 // parseSgrMouse attempts to locate an SGR mouse record at the start of the
@@ -1478,6 +2256,14 @@ func (t *tScreen) parseSgrMouse(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
 				_, _ = buf.ReadByte()
 				i--
 			}
+			if t.mouseEnc == 1016 && t.cellW > 0 && t.cellH > 0 {
+				// SGR-Pixels reports pixels, not cells
+				px, py := max(x, 0), max(y, 0)
+				ev := t.buildMouseEvent(px/t.cellW, py/t.cellH, btn)
+				ev.px, ev.py, ev.pixels = px, py, true
+				*evs = append(*evs, ev)
+				return true, true
+			}
 			*evs = append(*evs, t.buildMouseEvent(x, y, btn))
 			return true, true
 		}
@@ -1572,188 +2358,1343 @@ func (t *tScreen) parseClipboard(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
 }
 
 // parseXtermMouse is like parseSgrMouse, but it parses a legacy
-// X11 mouse record.
+// X11 mouse record, or one using the UTF-8 (1005) encoding.
 func (t *tScreen) parseXtermMouse(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
 
 	b := buf.Bytes()
 
-	state := 0
-	btn := 0
-	x := 0
-	y := 0
+	i := 0
+	switch {
+	case len(b) > 0 && b[0] == '\x9b':
+		i = 1
+	case len(b) > 1 && b[0] == '\x1b' && b[1] == '[':
+		i = 2
+	case len(b) == 1 && b[0] == '\x1b':
+		return true, false
+	default:
+		return false, false
+	}
+	if i == len(b) {
+		return true, false
+	}
+	if b[i] != 'M' {
+		return false, false
+	}
+	i++
 
-	for i := range b {
-		switch state {
-		case 0:
-			switch b[i] {
-			case '\x1b':
-				state = 1
-			case '\x9b':
-				state = 2
-			default:
-				return false, false
-			}
-		case 1:
-			if b[i] != '[' {
-				return false, false
-			}
-			state = 2
-		case 2:
-			if b[i] != 'M' {
-				return false, false
-			}
-			state++
-		case 3:
-			btn = int(b[i])
-			state++
-		case 4:
-			x = int(b[i]) - 32 - 1
-			state++
-		case 5:
-			y = int(b[i]) - 32 - 1
-			for i >= 0 {
-				_, _ = buf.ReadByte()
-				i--
+	// The button and the coordinates follow, offset by 32.  The UTF-8
+	// encoding sends them as characters, so that large coordinates fit.
+	var v [3]int
+	for j := range v {
+		if i == len(b) {
+			return true, false
+		}
+		if t.mouseEnc == 1005 {
+			if !utf8.FullRune(b[i:]) {
+				return true, false
 			}
-			*evs = append(*evs, t.buildMouseEvent(x, y, btn))
-			return true, true
+			r, n := utf8.DecodeRune(b[i:])
+			v[j] = int(r)
+			i += n
+		} else {
+			v[j] = int(b[i])
+			i++
 		}
 	}
-	return true, false
+	buf.Next(i)
+	*evs = append(*evs, t.buildMouseEvent(v[1]-32-1, v[2]-32-1, v[0]-32))
+	return true, true
 }
 
-func (t *tScreen) parseFunctionKey(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
-	b := buf.Bytes()
-	partial := false
-	for e, k := range t.keycodes {
-		esc := []byte(e)
-		if (len(esc) == 1) && (esc[0] == '\x1b') {
-			continue
-		}
-		if bytes.HasPrefix(b, esc) {
-			// matched
-			var r rune
-			if len(esc) == 1 {
-				r = rune(b[0])
-			}
-			mod := k.mod
-			if t.escaped {
-				mod |= ModAlt
-				t.escaped = false
-			}
-			switch k.key {
-			case keyPasteStart:
-				*evs = append(*evs, NewEventPaste(true))
-			case keyPasteEnd:
-				*evs = append(*evs, NewEventPaste(false))
-			default:
-				*evs = append(*evs, NewEventKey(k.key, r, mod))
-			}
-			for i := 0; i < len(esc); i++ {
-				_, _ = buf.ReadByte()
-			}
-			return true, true
-		}
-		if bytes.HasPrefix(esc, b) {
-			partial = true
+// parseUrxvtMouse parses a mouse record in the urxvt (1015) encoding:
+// CSI button ; x ; y M, with the values in decimal, but otherwise the
+// same as a legacy X11 record.
+func (t *tScreen) parseUrxvtMouse(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
+	n, params, final, partial := scanCSI(buf.Bytes())
+	if n == 0 {
+		return partial, false
+	}
+	if final != 'M' || len(params) == 0 || params[0] < '0' || params[0] > '9' {
+		return false, false
+	}
+	fields := parseCSIParams(params)
+	if len(fields) != 3 {
+		return false, false
+	}
+	buf.Next(n)
+	*evs = append(*evs, t.buildMouseEvent(fields[1][0]-1, fields[2][0]-1, fields[0][0]-32))
+	return true, true
+}
+
+// scanCSI examines the start of the buffer for a control sequence (CSI).
+// It returns the length of the complete sequence, the parameter bytes
+// (including any private marker such as '?' or '<'), and the final byte.
+// If the buffer only holds the start of a sequence, then n is zero and
+// partial is true.  If it does not start with CSI at all, both are zero.
+func scanCSI(b []byte) (n int, params []byte, final byte, partial bool) {
+	i := 0
+	switch {
+	case len(b) > 0 && b[0] == '\x9b':
+		i = 1
+	case len(b) > 1 && b[0] == '\x1b' && b[1] == '[':
+		i = 2
+	case len(b) == 1 && b[0] == '\x1b':
+		return 0, nil, 0, true
+	default:
+		return 0, nil, 0, false
+	}
+	start := i
+	for ; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c >= 0x20 && c <= 0x3f: // parameters and intermediates
+		case c >= 0x40 && c <= 0x7e:
+			return i + 1, b[start:i], c, false
+		default:
+			return 0, nil, 0, false
 		}
 	}
-	return partial, false
+	return 0, nil, 0, true
 }
 
-func (t *tScreen) parseRune(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
-	b := buf.Bytes()
-	if b[0] >= ' ' && b[0] <= 0x7F {
-		// printable ASCII easy to deal with -- no encodings
-		mod := ModNone
-		if t.escaped {
-			mod = ModAlt
-			t.escaped = false
+// parseCSIParams splits CSI parameters separated by ';' into fields, each
+// of which may hold sub-parameters separated by ':'.  Empty values are zero.
+func parseCSIParams(params []byte) [][]int {
+	var fields [][]int
+	field := []int{0}
+	for _, c := range params {
+		switch {
+		case c >= '0' && c <= '9':
+			field[len(field)-1] = field[len(field)-1]*10 + int(c-'0')
+		case c == ':':
+			field = append(field, 0)
+		case c == ';':
+			fields = append(fields, field)
+			field = []int{0}
 		}
-		*evs = append(*evs, NewEventKey(KeyRune, rune(b[0]), mod))
-		_, _ = buf.ReadByte()
+	}
+	return append(fields, field)
+}
+
+// parseModeReport parses a DEC private mode report (DECRPM), which is
+// sent in reply to a DECRQM query: CSI ? mode ; value $ y.  A value of 1
+// or 2 means the mode is recognized (and set or reset respectively).
+func (t *tScreen) parseModeReport(buf *bytes.Buffer, _ *[]Event) (bool, bool) {
+	n, params, final, partial := scanCSI(buf.Bytes())
+	if n == 0 {
+		return partial, false
+	}
+	if final != 'y' || len(params) < 2 || params[0] != '?' || params[len(params)-1] != '$' {
+		return false, false
+	}
+	fields := parseCSIParams(params[1 : len(params)-1])
+	buf.Next(n)
+	if len(fields) != 2 {
 		return true, true
 	}
+	mode, value := fields[0][0], fields[1][0]
+	if t.modes == nil {
+		t.modes = make(map[int]int)
+	}
+	t.modes[mode] = value
+	supported := value == 1 || value == 2
+	switch mode {
+	case 2026:
+		if supported && t.syncQuery != "" {
+			t.enterSync = "\x1b[?2026h"
+			t.exitSync = "\x1b[?2026l"
+		}
+	case 1005:
+		// this is the last of the mouse encodings we asked about,
+		// so now we can pick the best one
+		if t.mouseQuery != "" && t.mouseFlags != 0 {
+			t.enableMouse(t.mouseFlags)
+		}
+	case 2027:
+		// 2 means it can be set, 3 that it is permanently set
+		if value == 2 && t.gcQuery != "" && !t.gcMode {
+			t.TPuts("\x1b[?2027h")
+			t.gcMode = true
+		}
+	}
+	return true, true
+}
 
-	if b[0] < 0x80 {
-		// Low numbered values are control keys, not runes.
+// parseKittyReply looks for the reply to the kitty keyboard protocol
+// query: the current flags (CSI ? flags u).  This tells us that the
+// terminal supports the protocol, so we push our own flags.  (Terminals
+// without support just answer the device attributes query that follows.)
+func (t *tScreen) parseKittyReply(buf *bytes.Buffer, _ *[]Event) (bool, bool) {
+	n, params, final, partial := scanCSI(buf.Bytes())
+	if n == 0 {
+		return partial, false
+	}
+	if len(params) == 0 || params[0] != '?' {
+		return false, false
+	}
+	if final != 'u' {
+		return false, false
+	}
+	if !t.kittyKeys {
+		t.kittyKeys = true
+		t.TPuts(t.ti.TParm("\x1b[>%p1%du", t.kittyFlags()))
+	}
+	buf.Next(n)
+	return true, true
+}
+
+// parseGraphicsReply parses replies from the kitty graphics protocol
+// (APC G ... ST).  Our commands suppress replies, except for the query
+// sent to learn whether the terminal supports the protocol at all.
+func (t *tScreen) parseGraphicsReply(buf *bytes.Buffer, _ *[]Event) (bool, bool) {
+	n, data, partial := scanAPC(buf.Bytes())
+	if n == 0 {
+		return partial && couldBe(buf.Bytes(), "\x1b_G"), false
+	}
+	if len(data) == 0 || data[0] != 'G' {
 		return false, false
 	}
+	if bytes.HasPrefix(data, []byte("Gi=31;OK")) {
+		t.caps.KittyGraphics = true
+	}
+	buf.Next(n)
+	return true, true
+}
 
-	utf := make([]byte, 12)
-	for l := 1; l <= len(b); l++ {
-		t.decoder.Reset()
-		nOut, nIn, e := t.decoder.Transform(utf, b[:l], true)
-		if e == transform.ErrShortSrc {
+// parseQueryReply looks for replies to queries sent with sendQuery.  We
+// only look for these while we are waiting for them, as some (such as
+// cursor position reports) look just like keys with modifiers.
+func (t *tScreen) parseQueryReply(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
+	t.pruneQueries(time.Now())
+	for _, kind := range []ReplyKind{ReplyCSI, ReplyOSC, ReplyDCS, ReplyAPC} {
+		n, data, partial := scanReply(kind, buf.Bytes())
+		if n == 0 {
+			if partial && t.awaiting(kind) {
+				return true, false
+			}
 			continue
 		}
-		if nOut != 0 {
-			r, _ := utf8.DecodeRune(utf[:nOut])
-			if r != utf8.RuneError {
-				mod := ModNone
-				if t.escaped {
-					mod = ModAlt
-					t.escaped = false
-				}
-				*evs = append(*evs, NewEventKey(KeyRune, r, mod))
+		for i, q := range t.queries {
+			if !q.m.match(kind, data) {
+				continue
 			}
-			for nIn > 0 {
-				_, _ = buf.ReadByte()
-				nIn--
+			t.queries = append(t.queries[:i:i], t.queries[i+1:]...)
+			data = append([]byte{}, data...)
+			buf.Next(n)
+			if q.ch != nil {
+				q.ch <- data
+			} else if q.event != nil {
+				if ev := q.event(data); ev != nil {
+					*evs = append(*evs, ev)
+				}
 			}
 			return true, true
 		}
+		return false, false
 	}
-	// Looks like potential escape
-	return true, false
+	return false, false
 }
 
-func (t *tScreen) scanInput(buf *bytes.Buffer, expire bool) {
-	evs := t.collectEventsFromInput(buf, expire)
-
-	for _, ev := range evs {
-		select {
-		case t.eventQ <- ev:
-		case <-t.quit:
-			return
+// scanReply scans the start of the buffer for a control sequence of the
+// given kind, returning its length and content as for Query.
+func scanReply(kind ReplyKind, b []byte) (int, []byte, bool) {
+	switch kind {
+	case ReplyCSI:
+		n, params, final, partial := scanCSI(b)
+		if n == 0 {
+			return 0, nil, partial
 		}
+		return n, append(append([]byte{}, params...), final), false
+	case ReplyOSC:
+		return scanOSC(b)
+	case ReplyDCS:
+		return scanDCS(b)
+	case ReplyAPC:
+		return scanAPC(b)
 	}
+	return 0, nil, false
 }
 
-// Return an array of Events extracted from the supplied buffer. This is done
-// while holding the screen's lock - the events can then be queued for
-// application processing with the lock released.
-func (t *tScreen) collectEventsFromInput(buf *bytes.Buffer, expire bool) []Event {
+// parseDeviceAttrs parses the replies to the primary (CSI ? ... c) and
+// secondary (CSI > ... c) device attributes queries.  We always ask for the
+// primary attributes last, as every terminal answers it, so when its reply
+// arrives we know the terminal has answered any other queries it can.
+func (t *tScreen) parseDeviceAttrs(buf *bytes.Buffer, _ *[]Event) (bool, bool) {
+	n, params, final, partial := scanCSI(buf.Bytes())
+	if n == 0 {
+		return partial, false
+	}
+	if final != 'c' || len(params) == 0 || (params[0] != '?' && params[0] != '>') {
+		return false, false
+	}
+	buf.Next(n)
+	fields := parseCSIParams(params[1:])
+	if params[0] == '>' {
+		// The version is the second field, but is only a
+		// fallback for XTVERSION, which is more informative.
+		if len(fields) > 1 && t.caps.Version == "" {
+			t.caps.Version = strconv.Itoa(fields[1][0])
+		}
+		return true, true
+	}
+	for _, f := range fields[1:] {
+		if f[0] == 4 {
+			t.caps.Sixel = true
+		}
+	}
+	t.da1Seen++
+	if t.probeDone != nil && t.da1Seen >= t.probeWant {
+		t.caps.Probed = true
+		close(t.probeDone)
+		t.probeDone = nil
+	}
+	return true, true
+}
 
-	res := make([]Event, 0, 20)
+// scanDCS examines the start of the buffer for a device control string,
+// terminated by ST.  It returns the length of the complete string, and its
+// content.  If the buffer only holds the start of one, then n is zero and
+// partial is true.
+func scanDCS(b []byte) (n int, data []byte, partial bool) {
+	return scanString(b, 'P')
+}
 
-	t.Lock()
-	defer t.Unlock()
+// scanAPC is like scanDCS, but for application program commands.
+func scanAPC(b []byte) (n int, data []byte, partial bool) {
+	return scanString(b, '_')
+}
 
-	for {
-		b := buf.Bytes()
-		if len(b) == 0 {
-			buf.Reset()
-			return res
+// scanString scans for a control string introduced by ESC and the given
+// byte (or its C1 equivalent), and terminated by ST.
+func scanString(b []byte, intro byte) (n int, data []byte, partial bool) {
+	i := 0
+	switch {
+	case len(b) > 0 && b[0] == intro+0x40:
+		i = 1
+	case len(b) > 1 && b[0] == '\x1b' && b[1] == intro:
+		i = 2
+	case len(b) == 1 && b[0] == '\x1b':
+		return 0, nil, true
+	default:
+		return 0, nil, false
+	}
+	for j := i; j < len(b); j++ {
+		switch b[j] {
+		case '\x9c':
+			if j == i || b[j-1] < 0x80 {
+				return j + 1, b[i:j], false
+			}
+		case '\x1b':
+			if j+1 == len(b) {
+				return 0, nil, true
+			}
+			if b[j+1] == '\\' {
+				return j + 2, b[i:j], false
+			}
+			return 0, nil, false
 		}
+	}
+	return 0, nil, true
+}
 
-		partials := 0
-
-		if part, comp := t.parseRune(buf, &res); comp {
-			continue
-		} else if part {
-			partials++
+// couldBe reports whether the buffer might hold (the start of) a sequence
+// beginning with one of the given prefixes.
+func couldBe(b []byte, prefixes ...string) bool {
+	for _, p := range prefixes {
+		if len(b) < len(p) {
+			if strings.HasPrefix(p, string(b)) {
+				return true
+			}
+		} else if strings.HasPrefix(string(b), p) {
+			return true
 		}
+	}
+	return false
+}
 
-		if part, comp := t.parseFunctionKey(buf, &res); comp {
-			continue
-		} else if part {
-			partials++
+// scanOSC examines the start of the buffer for an operating system command,
+// terminated by either ST or BEL.  It returns the length of the complete
+// command, and its content.  If the buffer only holds the start of one,
+// then n is zero and partial is true.
+func scanOSC(b []byte) (n int, data []byte, partial bool) {
+	i := 0
+	switch {
+	case len(b) > 0 && b[0] == '\x9d':
+		i = 1
+	case len(b) > 1 && b[0] == '\x1b' && b[1] == ']':
+		i = 2
+	case len(b) == 1 && b[0] == '\x1b':
+		return 0, nil, true
+	default:
+		return 0, nil, false
+	}
+	for j := i; j < len(b); j++ {
+		switch b[j] {
+		case '\a':
+			return j + 1, b[i:j], false
+		case '\x9c':
+			// in UTF-8 this may be the end of a character, like ś
+			if j == i || b[j-1] < 0x80 {
+				return j + 1, b[i:j], false
+			}
+		case '\x1b':
+			if j+1 == len(b) {
+				return 0, nil, true
+			}
+			if b[j+1] == '\\' {
+				return j + 2, b[i:j], false
+			}
+			return 0, nil, false
 		}
+	}
+	return 0, nil, true
+}
 
-		if part, comp := t.parseFocus(buf, &res); comp {
-			continue
-		} else if part {
+// collectPaste gathers the content of a bracketed paste, when pastes are
+// delivered whole, and sends it once the end of the paste arrives.  It
+// returns false if more data is needed.
+func (t *tScreen) collectPaste(buf *bytes.Buffer, evs *[]Event) bool {
+	b := buf.Bytes()
+	n := bytes.Index(b, t.pasteEnd)
+	if n < 0 {
+		// keep anything that might be the start of the end marker
+		n = len(b)
+		for k := min(len(b), len(t.pasteEnd)-1); k > 0; k-- {
+			if bytes.HasPrefix(t.pasteEnd, b[len(b)-k:]) {
+				n -= k
+				break
+			}
+		}
+		t.addPaste(b[:n])
+		buf.Next(n)
+		return false
+	}
+	t.addPaste(b[:n])
+	buf.Next(n + len(t.pasteEnd))
+	t.endPaste(evs)
+	return true
+}
+
+// endPaste delivers the paste collected so far.
+func (t *tScreen) endPaste(evs *[]Event) {
+	t.pasting = false
+
+	// the terminal sends the text in its own encoding
+	data := t.pasteData
+	if t.charset != "UTF-8" {
+		if utf, _, err := transform.Bytes(t.decoder, data); err == nil {
+			data = utf
+		}
+	}
+	*evs = append(*evs, NewEventPasteText(string(data), t.pasteLimit))
+}
+
+// abandonPaste discards any paste being collected, so that input is
+// decoded normally again.
+func (t *tScreen) abandonPaste() {
+	t.pasting = false
+	t.pasteData = nil
+}
+
+// pasteTimeout is how long we wait for more of a paste, before assuming
+// that its end marker was lost.
+const pasteTimeout = time.Second
+
+// addPaste adds to the content of a paste, discarding what is well beyond
+// the limit.  (The exact limit is applied when the paste is complete.)
+func (t *tScreen) addPaste(b []byte) {
+	if len(b) > 0 {
+		t.pasteExpire = time.Now().Add(pasteTimeout)
+	}
+	if room := 2*t.pasteLimit + utf8.UTFMax - len(t.pasteData); len(b) > room {
+		b = b[:max(room, 0)]
+	}
+	t.pasteData = append(t.pasteData, b...)
+}
+
+// scanSequence examines the start of the buffer for any complete escape
+// sequence: a control sequence, a control string (OSC, DCS or APC), or a
+// single shift (SS3) with parameters.  It returns the length of
+// the sequence, or zero and whether more data might complete it.
+func scanSequence(b []byte) (n int, partial bool) {
+	if len(b) < 2 || b[0] != '\x1b' {
+		return 0, len(b) == 1 && b[0] == '\x1b'
+	}
+	switch b[1] {
+	case '[':
+		n, _, _, partial = scanCSI(b)
+	case ']':
+		n, _, partial = scanOSC(b)
+	case 'P', '_':
+		n, _, partial = scanString(b, b[1])
+	case 'O':
+		// Those without parameters are either keys we know, or just
+		// ESC (Alt) with O, followed by another key.
+		for i := 2; i < len(b); i++ {
+			c := b[i]
+			switch {
+			case c >= '0' && c <= ';': // parameters
+			case i > 2 && ((c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')):
+				return i + 1, false
+			default:
+				return 0, false
+			}
+		}
+		return 0, true
+	}
+	return n, partial
+}
+
+// parseUnknown delivers any complete escape sequence that no other parser
+// recognized as an EventRaw, instead of splitting it into keys.
+func (t *tScreen) parseUnknown(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
+	b := buf.Bytes()
+	n, partial := scanSequence(b)
+	if n == 0 {
+		return partial, false
+	}
+	*evs = append(*evs, NewEventRaw(bytes.Clone(b[:n])))
+	buf.Next(n)
+	t.escaped = false
+	return true, true
+}
+
+// setRaw records the bytes that were consumed for any keys that were
+// decoded from them.
+func setRaw(evs []Event, raw []byte) {
+	for _, ev := range evs {
+		if ev, ok := ev.(*EventKey); ok && ev.raw == nil {
+			ev.raw = bytes.Clone(raw)
+		}
+	}
+}
+
+// parseXColor parses a color specification as used by X11, and in
+// terminal replies: rgb:R/G/B with 1 to 4 hex digits per component,
+// or #RGB with the same number of digits for each.
+func parseXColor(s string) (Color, bool) {
+	var parts []string
+	switch {
+	case strings.HasPrefix(s, "rgb:"):
+		parts = strings.Split(s[4:], "/")
+	case strings.HasPrefix(s, "rgba:"):
+		parts = strings.Split(s[5:], "/")
+		if len(parts) == 4 {
+			parts = parts[:3]
+		}
+	case strings.HasPrefix(s, "#") && len(s) > 1 && (len(s)-1)%3 == 0:
+		d := (len(s) - 1) / 3
+		parts = []string{s[1 : 1+d], s[1+d : 1+2*d], s[1+2*d:]}
+	}
+	if len(parts) != 3 {
+		return ColorDefault, false
+	}
+	var rgb [3]int32
+	for i, p := range parts {
+		if len(p) < 1 || len(p) > 4 {
+			return ColorDefault, false
+		}
+		v, err := strconv.ParseUint(p, 16, 16)
+		if err != nil {
+			return ColorDefault, false
+		}
+		rgb[i] = int32(v * 255 / (1<<(4*len(p)) - 1))
+	}
+	return NewRGBColor(rgb[0], rgb[1], rgb[2]), true
+}
+
+// parseColorReply parses the replies to the default foreground (OSC 10)
+// and background (OSC 11) color queries.  The background is always asked
+// for last, so when it arrives we can report both.  It also handles the
+// replies to palette color queries (OSC 4).
+func (t *tScreen) parseColorReply(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
+	n, data, partial := scanOSC(buf.Bytes())
+	if n == 0 {
+		return partial && couldBe(buf.Bytes(), "\x1b]10;", "\x1b]11;", "\x1b]4;"), false
+	}
+	var fg bool
+	switch {
+	case bytes.HasPrefix(data, []byte("10;")):
+		fg = true
+	case bytes.HasPrefix(data, []byte("11;")):
+	case bytes.HasPrefix(data, []byte("4;")):
+		fields := strings.SplitN(string(data), ";", 3)
+		if len(fields) != 3 {
+			return false, false
+		}
+		buf.Next(n)
+		index, err := strconv.Atoi(fields[1])
+		if c, ok := parseXColor(fields[2]); ok && err == nil {
+			*evs = append(*evs, NewEventPaletteColor(index, c))
+		}
+		return true, true
+	default:
+		return false, false
+	}
+	buf.Next(n)
+	c, ok := parseXColor(string(data[3:]))
+	if !ok {
+		c = ColorDefault
+	}
+	if fg {
+		t.schemeFg = c
+		return true, true
+	}
+	*evs = append(*evs, NewEventColorScheme(t.schemeHint, t.schemeFg, c))
+	t.schemeFg = ColorDefault
+	t.schemeHint = ColorSchemeUnknown
+	return true, true
+}
+
+// parseSchemeReport parses the color scheme report (CSI ? 997 ; 1 n for
+// dark, or 2 for light) sent when the terminal's colors change, if enabled
+// by mode 2031.  We then ask for the actual colors, and report the change
+// when they arrive.
+func (t *tScreen) parseSchemeReport(buf *bytes.Buffer, _ *[]Event) (bool, bool) {
+	n, params, final, partial := scanCSI(buf.Bytes())
+	if n == 0 {
+		return partial, false
+	}
+	if final != 'n' || !bytes.HasPrefix(params, []byte("?997;")) {
+		return false, false
+	}
+	buf.Next(n)
+	switch parseCSIParams(params[1:])[1][0] {
+	case 1:
+		t.schemeHint = ColorSchemeDark
+	case 2:
+		t.schemeHint = ColorSchemeLight
+	}
+	t.TPuts(t.queryColors)
+	return true, true
+}
+
+// parseTermReply parses replies to XTVERSION (DCS > | text ST) and to
+// XTGETTCAP (DCS 1 + r name ST, or DCS 0 + r ST if not known).
+func (t *tScreen) parseTermReply(buf *bytes.Buffer, _ *[]Event) (bool, bool) {
+	n, data, partial := scanDCS(buf.Bytes())
+	if n == 0 {
+		// only keep waiting if this could still be one of ours
+		return partial && couldBe(buf.Bytes(), "\x1bP>|", "\x1bP1+r", "\x1bP0+r"), false
+	}
+	switch {
+	case bytes.HasPrefix(data, []byte(">|")):
+		version := string(data[2:])
+		if i := strings.IndexByte(version, '('); i > 0 && strings.HasSuffix(version, ")") {
+			t.caps.Name = version[:i]
+			t.caps.Version = version[i+1 : len(version)-1]
+		} else if i := strings.IndexByte(version, ' '); i > 0 {
+			t.caps.Name = version[:i]
+			t.caps.Version = version[i+1:]
+		} else {
+			t.caps.Name = version
+		}
+	case bytes.HasPrefix(data, []byte("1+r")):
+		for _, c := range strings.Split(string(data[3:]), ";") {
+			name, _, _ := strings.Cut(c, "=")
+			switch strings.ToUpper(name) {
+			case "524742", "5463": // RGB, Tc
+				t.caps.TrueColor = true
+			}
+		}
+	case bytes.HasPrefix(data, []byte("0+r")):
+	default:
+		return false, false
+	}
+	buf.Next(n)
+	return true, true
+}
+
+// probeQuery is sent to ask the terminal about its capabilities: XTVERSION,
+// secondary device attributes, the state of the private modes we care about,
+// the RGB and Tc capabilities via XTGETTCAP, and finally the primary device
+// attributes (which also tells us about sixel support).
+const probeQuery = "\x1b[>0q\x1b[>c" +
+	"\x1b[?1004$p\x1b[?2004$p\x1b[?2026$p\x1b[?2027$p" +
+	"\x1bP+q524742;5463\x1b\\" +
+	"\x1b[c"
+
+func (t *tScreen) ProbeCapabilities(timeout time.Duration) Capabilities {
+	t.Lock()
+	if t.running && !strings.Contains(t.ti.Name, "linux") && (t.ti.Mouse != "" || t.ti.XTermLike) {
+		done := make(chan struct{})
+		t.probeDone = done
+		t.probeSent = true
+		t.da1Sent++
+		t.probeWant = t.da1Sent
+		t.TPuts(t.gfxQuery)
+		t.TPuts(probeQuery)
+		t.Unlock()
+
+		select {
+		case <-done:
+		case <-time.After(timeout):
+		}
+
+		t.Lock()
+		if t.probeDone == done {
+			// no reply, give up on it
+			t.probeDone = nil
+			t.da1Seen = t.da1Sent
+		}
+	}
+	t.Unlock()
+	return t.Capabilities()
+}
+
+func (t *tScreen) ProbeWidths(timeout time.Duration) WidthPolicy {
+	t.Lock()
+	wp := t.cells.GetWidthPolicy()
+	// A box drawing character, as these are most often what goes
+	// wrong, is written at the top left, and we ask where it left
+	// the cursor.  The cells are drawn over by the next update.
+	// If we can't send the character itself, there's no point.
+	probe := string(t.encodeRune('\u2500', nil))
+	if !t.running || t.cprQuery == "" || probe == "?" || probe == t.acs['\u2500'] {
+		t.Unlock()
+		return wp
+	}
+	q := &tQuery{m: cprMatch, ch: make(chan []byte, 1)}
+	t.gotoXY(0, 0)
+	t.sendQuery(probe+t.cprQuery, q)
+	t.cx, t.cy = -1, -1
+	t.cells.SetDirty(0, 0, true)
+	t.cells.SetDirty(1, 0, true)
+	t.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if data, err := t.waitQuery(ctx, q); err == nil {
+		if x, _, ok := parseCursorReport(data); ok && (x == 1 || x == 2) {
+			t.Lock()
+			wp.AmbiguousWide = x == 2
+			t.cells.SetWidthPolicy(wp)
+			t.Unlock()
+		}
+	}
+	return wp
+}
+
+// cprMatch matches cursor position reports (CSI row ; col R).
+var cprMatch = MatchCSI("", 'R')
+
+// parseCursorReport returns the column and row, counting from zero,
+// of a cursor position report.
+func parseCursorReport(data []byte) (int, int, bool) {
+	if data[0] < '0' || data[0] > '9' {
+		return 0, 0, false
+	}
+	fields := parseCSIParams(data[:len(data)-1])
+	if len(fields) != 2 {
+		return 0, 0, false
+	}
+	return fields[1][0] - 1, fields[0][0] - 1, true
+}
+
+func (t *tScreen) QueryCursorPosition() {
+	t.Lock()
+	if t.running && t.cprQuery != "" {
+		t.sendQuery(t.cprQuery, &tQuery{m: cprMatch, expire: time.Now().Add(postQueryTimeout),
+			event: func(data []byte) Event {
+				if x, y, ok := parseCursorReport(data); ok {
+					return NewEventCursorPosition(x, y)
+				}
+				return nil
+			}})
+	}
+	t.Unlock()
+}
+
+func (t *tScreen) GetCursorPosition(timeout time.Duration) (int, int, bool) {
+	t.Lock()
+	if !t.running || t.cprQuery == "" {
+		t.Unlock()
+		return 0, 0, false
+	}
+	q := &tQuery{m: cprMatch, ch: make(chan []byte, 1)}
+	t.sendQuery(t.cprQuery, q)
+	t.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	data, err := t.waitQuery(ctx, q)
+	if err != nil {
+		return 0, 0, false
+	}
+	return parseCursorReport(data)
+}
+
+// tQuery is a query sent to the terminal, waiting for its reply.
+type tQuery struct {
+	m      ReplyMatcher
+	ch     chan []byte        // for a caller waiting for the reply, or
+	event  func([]byte) Event // to post the reply as an event
+	expire time.Time          // when an abandoned query is forgotten
+}
+
+// queryGrace is how long we keep looking for the reply to a query that
+// was abandoned, so that a late reply is not taken for input.
+const queryGrace = time.Second
+
+// postQueryTimeout is how long we wait for the reply to a query whose
+// reply is posted as an event.  Without a limit, a terminal that ignores
+// the query would have us take matching input (such as F3 with modifiers,
+// for a cursor position report) as the reply forever.
+const postQueryTimeout = time.Second
+
+func (t *tScreen) Query(ctx context.Context, query string, m ReplyMatcher) ([]byte, error) {
+	t.Lock()
+	if !t.running {
+		t.Unlock()
+		return nil, ErrNoScreen
+	}
+	q := &tQuery{m: m, ch: make(chan []byte, 1)}
+	t.sendQuery(query, q)
+	t.Unlock()
+	return t.waitQuery(ctx, q)
+}
+
+func (t *tScreen) PostQuery(query string, m ReplyMatcher) {
+	t.Lock()
+	if t.running {
+		t.sendQuery(query, &tQuery{m: m, expire: time.Now().Add(postQueryTimeout),
+			event: func(data []byte) Event {
+				return NewEventReply(m.Kind, data)
+			}})
+	}
+	t.Unlock()
+}
+
+// sendQuery sends a query to the terminal, and starts looking for the
+// reply.  Replies are matched to queries in the order they were sent.
+// The caller must hold the lock.
+func (t *tScreen) sendQuery(query string, q *tQuery) {
+	t.queries = append(t.queries, q)
+	t.TPuts(query)
+}
+
+// waitQuery waits for the reply to a query, which must have a channel.
+func (t *tScreen) waitQuery(ctx context.Context, q *tQuery) ([]byte, error) {
+	select {
+	case data, ok := <-q.ch:
+		if !ok {
+			return nil, ErrNoScreen
+		}
+		return data, nil
+	case <-ctx.Done():
+		t.Lock()
+		q.expire = time.Now().Add(queryGrace)
+		t.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// pruneQueries forgets queries abandoned long enough ago.
+func (t *tScreen) pruneQueries(now time.Time) {
+	keep := t.queries[:0]
+	for _, q := range t.queries {
+		if q.expire.IsZero() || now.Before(q.expire) {
+			keep = append(keep, q)
+		}
+	}
+	t.queries = keep
+}
+
+// awaiting reports whether we are waiting for a reply of the given kind.
+func (t *tScreen) awaiting(kind ReplyKind) bool {
+	for _, q := range t.queries {
+		if q.m.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// abortQueries gives up on all queries, as the terminal is released.
+func (t *tScreen) abortQueries() {
+	for _, q := range t.queries {
+		if q.ch != nil {
+			close(q.ch)
+		}
+	}
+	t.queries = nil
+}
+
+func (t *tScreen) Capabilities() Capabilities {
+	t.Lock()
+	defer t.Unlock()
+	c := t.caps
+	c.TrueColor = c.TrueColor || t.truecolor
+	c.SyncOutput = t.enterSync != ""
+	c.KittyKeyboard = t.kittyKeys
+	if v, ok := t.modes[2027]; ok {
+		c.Graphemes = v == 1 || v == 2 || v == 3
+	}
+	// a mode reported as not recognized (0) or permanently reset (4)
+	// is not supported; if the terminal did not say, we guess.
+	if v, ok := t.modes[1004]; ok {
+		c.Focus = v != 0 && v != 4
+	} else {
+		c.Focus = t.enableFocus != ""
+	}
+	if v, ok := t.modes[2004]; ok {
+		c.BracketedPaste = v != 0 && v != 4
+	} else {
+		c.BracketedPaste = t.enablePaste != ""
+	}
+	return c
+}
+
+// kittyFuncKeys maps the private use code points used by the kitty keyboard
+// protocol for functional keys.  Keypad keys that produce text map to KeyRune.
+var kittyFuncKeys = map[int]tKeyCode{
+	57361: {key: KeyPrint},
+	57362: {key: KeyPause},
+	57376: {key: KeyF13},
+	57377: {key: KeyF14},
+	57378: {key: KeyF15},
+	57379: {key: KeyF16},
+	57380: {key: KeyF17},
+	57381: {key: KeyF18},
+	57382: {key: KeyF19},
+	57383: {key: KeyF20},
+	57384: {key: KeyF21},
+	57385: {key: KeyF22},
+	57386: {key: KeyF23},
+	57387: {key: KeyF24},
+	57388: {key: KeyF25},
+	57389: {key: KeyF26},
+	57390: {key: KeyF27},
+	57391: {key: KeyF28},
+	57392: {key: KeyF29},
+	57393: {key: KeyF30},
+	57394: {key: KeyF31},
+	57395: {key: KeyF32},
+	57396: {key: KeyF33},
+	57397: {key: KeyF34},
+	57398: {key: KeyF35},
+	57414: {key: KeyEnter},
+	57417: {key: KeyLeft},
+	57418: {key: KeyRight},
+	57419: {key: KeyUp},
+	57420: {key: KeyDown},
+	57421: {key: KeyPgUp},
+	57422: {key: KeyPgDn},
+	57423: {key: KeyHome},
+	57424: {key: KeyEnd},
+	57425: {key: KeyInsert},
+	57426: {key: KeyDelete},
+	57427: {key: KeyCenter},
+}
+
+// kittyKeypadRunes maps the keypad code points that produce text.
+var kittyKeypadRunes = map[int]rune{
+	57399: '0', 57400: '1', 57401: '2', 57402: '3', 57403: '4',
+	57404: '5', 57405: '6', 57406: '7', 57407: '8', 57408: '9',
+	57409: '.', 57410: '/', 57411: '*', 57412: '-', 57413: '+',
+	57415: '=', 57416: ',',
+}
+
+// kittyTildeKeys are the keys reported as CSI number ~.
+var kittyTildeKeys = map[int]Key{
+	2:     KeyInsert,
+	3:     KeyDelete,
+	5:     KeyPgUp,
+	6:     KeyPgDn,
+	7:     KeyHome,
+	8:     KeyEnd,
+	11:    KeyF1,
+	12:    KeyF2,
+	13:    KeyF3,
+	14:    KeyF4,
+	15:    KeyF5,
+	17:    KeyF6,
+	18:    KeyF7,
+	19:    KeyF8,
+	20:    KeyF9,
+	21:    KeyF10,
+	23:    KeyF11,
+	24:    KeyF12,
+	57427: KeyCenter,
+}
+
+// kittyLetterKeys are the keys reported as CSI 1 ; modifiers letter.
+// Note that F3 (R) is absent, because kitty reports it as CSI 13 ~ to
+// avoid confusion with cursor position reports.
+var kittyLetterKeys = map[byte]Key{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+	'E': KeyCenter,
+	'F': KeyEnd,
+	'H': KeyHome,
+	'P': KeyF1,
+	'Q': KeyF2,
+	'S': KeyF4,
+}
+
+// kittyMods converts the kitty modifier encoding (one plus a bit mask).
+func kittyMods(m int) ModMask {
+	mod := ModNone
+	if m < 1 {
+		return mod
+	}
+	m--
+	if m&1 != 0 {
+		mod |= ModShift
+	}
+	if m&2 != 0 {
+		mod |= ModAlt
+	}
+	if m&4 != 0 {
+		mod |= ModCtrl
+	}
+	if m&8 != 0 {
+		mod |= ModSuper
+	}
+	if m&16 != 0 {
+		mod |= ModHyper
+	}
+	if m&32 != 0 {
+		mod |= ModMeta
+	}
+	if m&64 != 0 {
+		mod |= ModCapsLock
+	}
+	if m&128 != 0 {
+		mod |= ModNumLock
+	}
+	return mod
+}
+
+// parseKittyKey parses keys encoded using the kitty keyboard protocol.  This
+// covers CSI u sequences, as well as the legacy CSI ~ and CSI letter forms,
+// which gain an event type sub-parameter when the protocol is engaged.
+func (t *tScreen) parseKittyKey(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
+	n, params, final, partial := scanCSI(buf.Bytes())
+	if n == 0 {
+		return partial, false
+	}
+	for _, c := range params {
+		if (c < '0' || c > '9') && c != ';' && c != ':' {
+			return false, false
+		}
+	}
+	fields := parseCSIParams(params)
+	code := fields[0][0]
+	mod := ModNone
+	et := KeyEventPress
+	if len(fields) > 1 {
+		mod = kittyMods(fields[1][0])
+		if len(fields[1]) > 1 && fields[1][1] > 1 {
+			et = KeyEventType(fields[1][1] - 1)
+		}
+	}
+
+	key := KeyRune
+	var ch rune
+	switch final {
+	case 'u':
+		switch code {
+		case 9:
+			key = KeyTab
+			if mod&ModShift != 0 {
+				key = KeyBacktab
+				mod &^= ModShift
+			}
+		case 13:
+			key = KeyEnter
+		case 27:
+			key = KeyEsc
+		case 127:
+			key = KeyBackspace
+		default:
+			if k, ok := kittyFuncKeys[code]; ok {
+				key = k.key
+			} else if r, ok := kittyKeypadRunes[code]; ok {
+				ch = r
+			} else if code >= 57344 && code <= 63743 {
+				// Lock and modifier keys, media keys, and so forth.
+				// We have no way to represent these.
+				buf.Next(n)
+				return true, true
+			} else if mod&ModCtrl != 0 && ctrlKey(rune(code)) >= 0 {
+				key = ctrlKey(rune(code))
+				ch = rune(key)
+			} else if len(fields) > 2 && fields[2][0] != 0 {
+				// associated text is the best rendition
+				ch = rune(fields[2][0])
+			} else if mod&ModShift != 0 && len(fields[0]) > 1 && fields[0][1] != 0 {
+				ch = rune(fields[0][1])
+			} else {
+				ch = rune(code)
+			}
+		}
+	case '~':
+		k, ok := kittyTildeKeys[code]
+		if !ok {
+			// bracketed paste markers and the like are not keys
+			return false, false
+		}
+		key = k
+	default:
+		k, ok := kittyLetterKeys[final]
+		if !ok {
+			return false, false
+		}
+		key = k
+	}
+	buf.Next(n)
+
+	switch et {
+	case KeyEventRepeat:
+		if t.kbdFlags&KeyRepeatEvents == 0 {
+			return true, true
+		}
+	case KeyEventRelease:
+		if t.kbdFlags&KeyReleaseEvents == 0 {
+			return true, true
+		}
+	}
+	if t.escaped {
+		mod |= ModAlt
+		t.escaped = false
+	}
+	*evs = append(*evs, newEventKeyType(key, ch, mod, et))
+	return true, true
+}
+
+// ctrlKey returns the control key that is typed by holding the Control key
+// along with the given rune, or -1 if there is none.
+func ctrlKey(r rune) Key {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return Key(r-'a') + KeyCtrlA
+	case r == ' ' || r == '@':
+		return KeyCtrlSpace
+	case r >= '[' && r <= '_':
+		return Key(r-'[') + KeyCtrlLeftSq
+	}
+	return -1
+}
+
+func (t *tScreen) parseFunctionKey(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
+	b := buf.Bytes()
+	k, n, partial := t.keys.match(b)
+	if k == nil || (n == 1 && b[0] == '\x1b') {
+		return partial, false
+	}
+	var r rune
+	if n == 1 {
+		r = rune(b[0])
+	}
+	mod := k.mod
+	if t.escaped {
+		mod |= ModAlt
+		t.escaped = false
+	}
+	switch k.key {
+	case keyPasteStart:
+		if t.pasteLimit > 0 && len(t.pasteEnd) > 0 {
+			t.pasting = true
+			t.pasteData = t.pasteData[:0]
+			t.pasteExpire = time.Now().Add(pasteTimeout)
+			break
+		}
+		*evs = append(*evs, NewEventPaste(true))
+	case keyPasteEnd:
+		*evs = append(*evs, NewEventPaste(false))
+	default:
+		*evs = append(*evs, NewEventKey(k.key, r, mod))
+	}
+	buf.Next(n)
+	return true, true
+}
+
+func (t *tScreen) parseRune(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
+	b := buf.Bytes()
+	if b[0] >= ' ' && b[0] <= 0x7F {
+		// printable ASCII easy to deal with -- no encodings
+		mod := ModNone
+		if t.escaped {
+			mod = ModAlt
+			t.escaped = false
+		}
+		*evs = append(*evs, NewEventKey(KeyRune, rune(b[0]), mod))
+		_, _ = buf.ReadByte()
+		return true, true
+	}
+
+	if b[0] < 0x80 {
+		// Low numbered values are control keys, not runes.
+		return false, false
+	}
+
+	utf := make([]byte, 12)
+	for l := 1; l <= len(b); l++ {
+		t.decoder.Reset()
+		nOut, nIn, e := t.decoder.Transform(utf, b[:l], true)
+		if e == transform.ErrShortSrc {
+			continue
+		}
+		if nOut != 0 {
+			r, _ := utf8.DecodeRune(utf[:nOut])
+			if r != utf8.RuneError {
+				mod := ModNone
+				if t.escaped {
+					mod = ModAlt
+					t.escaped = false
+				}
+				*evs = append(*evs, NewEventKey(KeyRune, r, mod))
+			}
+			for nIn > 0 {
+				_, _ = buf.ReadByte()
+				nIn--
+			}
+			return true, true
+		}
+	}
+	// Looks like potential escape
+	return true, false
+}
+
+func (t *tScreen) scanInput(buf *bytes.Buffer, expire bool) {
+	evs := t.collectEventsFromInput(buf, expire)
+
+	for _, ev := range evs {
+		if t.eventQ.post(ev, t.quit) != nil {
+			return
+		}
+	}
+}
+
+// Return an array of Events extracted from the supplied buffer. This is done
+// while holding the screen's lock - the events can then be queued for
+// application processing with the lock released.
+func (t *tScreen) collectEventsFromInput(buf *bytes.Buffer, expire bool) []Event {
+
+	res := make([]Event, 0, 20)
+
+	t.Lock()
+	defer t.Unlock()
+
+	start, mark := 0, buf.Bytes()
+	for {
+		// Give the bytes consumed by the last pass to the keys that
+		// were decoded from them.  An ESC prefix is kept for the key
+		// that follows it.
+		if !t.escaped {
+			if n := len(mark) - buf.Len(); n > 0 {
+				setRaw(res[start:], mark[:n])
+			}
+			start, mark = len(res), buf.Bytes()
+		}
+
+		b := buf.Bytes()
+		if t.pasting {
+			if t.collectPaste(buf, &res) {
+				continue
+			}
+			// if the rest of the paste is overdue, deliver what we have
+			// (including what was kept in case it was the end marker)
+			if expire && time.Now().After(t.pasteExpire) {
+				t.addPaste(buf.Bytes())
+				buf.Reset()
+				t.endPaste(&res)
+				continue
+			}
+			break
+		}
+
+		if len(b) == 0 {
+			buf.Reset()
+			return res
+		}
+
+		partials := 0
+
+		if part, comp := t.parseRune(buf, &res); comp {
+			continue
+		} else if part {
+			partials++
+		}
+
+		if len(t.queries) > 0 {
+			if part, comp := t.parseQueryReply(buf, &res); comp {
+				continue
+			} else if part {
+				partials++
+			}
+		}
+
+		if part, comp := t.parseModeReport(buf, &res); comp {
+			continue
+		} else if part {
+			partials++
+		}
+
+		if part, comp := t.parseDeviceAttrs(buf, &res); comp {
+			continue
+		} else if part {
+			partials++
+		}
+
+		if t.probeSent {
+			if part, comp := t.parseTermReply(buf, &res); comp {
+				continue
+			} else if part {
+				partials++
+			}
+		}
+
+		if t.queryColors != "" || t.queryPalette != "" {
+			if part, comp := t.parseColorReply(buf, &res); comp {
+				continue
+			} else if part {
+				partials++
+			}
+
+			if part, comp := t.parseSchemeReport(buf, &res); comp {
+				continue
+			} else if part {
+				partials++
+			}
+		}
+
+		if t.gfxQuery != "" {
+			if part, comp := t.parseGraphicsReply(buf, &res); comp {
+				continue
+			} else if part {
+				partials++
+			}
+		}
+
+		if t.kittyQuery != "" {
+			if part, comp := t.parseKittyReply(buf, &res); comp {
+				continue
+			} else if part {
+				partials++
+			}
+		}
+
+		if t.kittyKeys {
+			if part, comp := t.parseKittyKey(buf, &res); comp {
+				continue
+			} else if part {
+				partials++
+			}
+		}
+
+		if part, comp := t.parseFunctionKey(buf, &res); comp {
+			continue
+		} else if part {
+			partials++
+		}
+
+		if part, comp := t.parseFocus(buf, &res); comp {
+			continue
+		} else if part {
 			partials++
 		}
 
@@ -1772,6 +3713,14 @@ func (t *tScreen) collectEventsFromInput(buf *bytes.Buffer, expire bool) []Event
 			} else if part {
 				partials++
 			}
+
+			if t.mouseEnc == 1015 {
+				if part, comp := t.parseUrxvtMouse(buf, &res); comp {
+					continue
+				} else if part {
+					partials++
+				}
+			}
 		}
 
 		if t.setClipboard != "" {
@@ -1782,6 +3731,12 @@ func (t *tScreen) collectEventsFromInput(buf *bytes.Buffer, expire bool) []Event
 			}
 		}
 
+		if part, comp := t.parseUnknown(buf, &res); comp {
+			continue
+		} else if part {
+			partials++
+		}
+
 		if partials == 0 || expire {
 			if b[0] == '\x1b' {
 				if len(b) == 1 {
@@ -1839,23 +3794,23 @@ func (t *tScreen) mainLoop(stopQ chan struct{}) {
 			// then we assume the escape sequence reached its
 			// conclusion, and process the chunk independently.
 			// This lets us detect conflicts such as a lone ESC.
-			if buf.Len() > 0 {
+			if t.inputPending(buf) {
 				if time.Now().After(t.keyexpire) {
 					t.scanInput(buf, true)
 				}
 			}
-			if buf.Len() > 0 {
+			if t.inputPending(buf) {
 				if !t.keytimer.Stop() {
 					select {
 					case <-t.keytimer.C:
 					default:
 					}
 				}
-				t.keytimer.Reset(time.Millisecond * 50)
+				t.keytimer.Reset(t.opts.escTimeout)
 			}
 		case chunk := <-t.keychan:
 			buf.Write(chunk)
-			t.keyexpire = time.Now().Add(time.Millisecond * 50)
+			t.keyexpire = time.Now().Add(t.opts.escTimeout)
 			t.scanInput(buf, false)
 			if !t.keytimer.Stop() {
 				select {
@@ -1863,13 +3818,21 @@ func (t *tScreen) mainLoop(stopQ chan struct{}) {
 				default:
 				}
 			}
-			if buf.Len() > 0 {
-				t.keytimer.Reset(time.Millisecond * 50)
+			if t.inputPending(buf) {
+				t.keytimer.Reset(t.opts.escTimeout)
 			}
 		}
 	}
 }
 
+// inputPending reports whether we are waiting for more input to finish
+// decoding what we have, either a sequence or a paste.
+func (t *tScreen) inputPending(buf *bytes.Buffer) bool {
+	t.Lock()
+	defer t.Unlock()
+	return buf.Len() > 0 || t.pasting
+}
+
 func (t *tScreen) inputLoop(stopQ chan struct{}) {
 
 	defer t.wg.Done()
@@ -1888,10 +3851,7 @@ func (t *tScreen) inputLoop(stopQ chan struct{}) {
 			running := t.running
 			t.Unlock()
 			if running {
-				select {
-				case t.eventQ <- NewEventError(e):
-				case <-t.quit:
-				}
+				_ = t.eventQ.post(NewEventError(e), t.quit)
 			}
 			return
 		}
@@ -2014,7 +3974,11 @@ func (t *tScreen) engage() error {
 	}
 	t.running = true
 	if ws, err := t.tty.WindowSize(); err == nil && ws.Width != 0 && ws.Height != 0 {
+		t.setCellSize(ws)
+		rows := ws.Height
+		ws = t.inlineSize(ws)
 		t.cells.Resize(ws.Width, ws.Height)
+		t.origin = max(rows-ws.Height, 0)
 	}
 	stopQ := make(chan struct{})
 	t.stopQ = stopQ
@@ -2023,9 +3987,18 @@ func (t *tScreen) engage() error {
 	if t.focusEnabled {
 		t.enableFocusReporting()
 	}
+	if t.schemeOn {
+		t.enableColorScheme()
+	}
+	t.hideImages()
+	for i, c := range t.palColors {
+		t.sendPalette(i, c)
+	}
 
 	ti := t.ti
-	if os.Getenv("TCELL_ALTSCREEN") != "disable" {
+	if t.inline > 0 {
+		t.makeRoom()
+	} else if t.opts.useAltScreen() {
 		// Technically this may not be right, but every terminal we know about
 		// (even Wyse 60) uses this to enter the alternate screen buffer, and
 		// possibly save and restore the window title and/or icon.
@@ -2040,10 +4013,33 @@ func (t *tScreen) engage() error {
 	t.TPuts(ti.HideCursor)
 	t.TPuts(ti.EnableAcs)
 	t.TPuts(ti.DisableAutoMargin)
-	t.TPuts(ti.Clear)
+	if t.inline > 0 {
+		t.TPuts(t.clearEos)
+	} else {
+		t.TPuts(ti.Clear)
+	}
 	if t.title != "" && t.setTitle != "" {
 		t.TPuts(t.ti.TParm(t.setTitle, t.title))
 	}
+	if t.setMouse != "" && t.mouseShape != MouseShapeDefault {
+		t.TPuts(t.ti.TParm(t.setMouse, mouseShapeNames[t.mouseShape]))
+	}
+	if t.syncQuery != "" {
+		t.TPuts(t.syncQuery)
+	}
+	if t.gcQuery != "" {
+		t.TPuts(t.gcQuery)
+	}
+	if t.mouseQuery != "" {
+		t.TPuts(t.mouseQuery)
+	}
+	if t.gfxQuery != "" {
+		t.TPuts(t.gfxQuery)
+	}
+	if t.kittyQuery != "" {
+		t.TPuts(t.kittyQuery)
+		t.da1Sent++
+	}
 
 	t.wg.Add(2)
 	go t.inputLoop(stopQ)
@@ -2063,6 +4059,8 @@ func (t *tScreen) disengage() {
 		return
 	}
 	t.running = false
+	t.abortQueries()
+	t.abandonPaste()
 	stopQ := t.stopQ
 	close(stopQ)
 	_ = t.tty.Drain()
@@ -2086,7 +4084,23 @@ func (t *tScreen) disengage() {
 	t.TPuts(ti.AttrOff)
 	t.TPuts(ti.ExitKeypad)
 	t.TPuts(ti.EnableAutoMargin)
-	if os.Getenv("TCELL_ALTSCREEN") != "disable" {
+	if t.kittyKeys {
+		// pop our flags, restoring whatever was in effect before
+		t.TPuts("\x1b[<u")
+		t.kittyKeys = false
+	}
+	if t.gcMode {
+		t.TPuts("\x1b[?2027l")
+		t.gcMode = false
+	}
+	if t.setMouse != "" && t.mouseShape != MouseShapeDefault {
+		t.TPuts(t.ti.TParm(t.setMouse, mouseShapeNames[MouseShapeDefault]))
+	}
+	if t.inline > 0 {
+		// leave our last frame in place, with the cursor below it
+		t.gotoXY(0, t.h-1)
+		t.writeString("\r\n")
+	} else if t.opts.useAltScreen() {
 		if t.restoreTitle != "" {
 			t.TPuts(t.restoreTitle)
 		}
@@ -2096,6 +4110,11 @@ func (t *tScreen) disengage() {
 	t.enableMouse(0)
 	t.enablePasting(false)
 	t.disableFocusReporting()
+	t.disableColorScheme()
+	if t.palColors != nil {
+		t.TPuts(t.resetPalette)
+	}
+	t.freeImages()
 
 	_ = t.tty.Stop()
 }
@@ -2117,7 +4136,7 @@ func (t *tScreen) StopQ() <-chan struct{} {
 	return t.quit
 }
 
-func (t *tScreen) EventQ() chan Event {
+func (t *tScreen) EventQ() *eventQueue {
 	return t.eventQ
 }
 
@@ -2134,6 +4153,17 @@ func (t *tScreen) SetTitle(title string) {
 	t.Unlock()
 }
 
+func (t *tScreen) SetMouseShape(shape MouseShape) {
+	t.Lock()
+	if name, ok := mouseShapeNames[shape]; ok && shape != t.mouseShape {
+		t.mouseShape = shape
+		if t.setMouse != "" && t.running {
+			t.TPuts(t.ti.TParm(t.setMouse, name))
+		}
+	}
+	t.Unlock()
+}
+
 func (t *tScreen) SetClipboard(data []byte) {
 	// Post binary data to the system clipboard.  It might be UTF-8, it might not be.
 	t.Lock()
@@ -2151,3 +4181,29 @@ func (t *tScreen) GetClipboard() {
 	}
 	t.Unlock()
 }
+
+func (t *tScreen) GetClipboardContext(ctx context.Context) ([]byte, error) {
+	t.Lock()
+	if !t.running {
+		t.Unlock()
+		return nil, ErrNoScreen
+	}
+	if t.setClipboard == "" {
+		t.Unlock()
+		return nil, ErrNotSupported
+	}
+	q := &tQuery{m: MatchOSC("52;"), ch: make(chan []byte, 1)}
+	t.sendQuery(t.ti.TParm(t.setClipboard, "?"), q)
+	t.Unlock()
+
+	data, err := t.waitQuery(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	// the reply is 52;selection;data, with the data in base64
+	fields := bytes.SplitN(data, []byte{';'}, 3)
+	if len(fields) != 3 {
+		return nil, errors.New("malformed clipboard reply")
+	}
+	return base64.StdEncoding.DecodeString(string(fields[2]))
+}
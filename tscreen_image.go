@@ -0,0 +1,200 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package tcell
+
+import (
+	"image"
+	"reflect"
+)
+
+// tImage is an image drawn over a region of cells.
+type tImage struct {
+	x, y       int
+	cols, rows int
+	img        image.Image
+	under      []imageCell // content of the cells when the image was drawn
+	data       []byte      // encoded image
+	cw, ch     int         // cell size the image was encoded for
+	shown      bool
+}
+
+type imageCell struct {
+	mainc rune
+	combc []rune
+	style Style
+}
+
+func (t *tScreen) DrawImage(x, y, cols, rows int, img image.Image) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	if img != nil {
+		// Drawing the same image again keeps it, as long as the
+		// content under it is what it is now.
+		for _, im := range t.images {
+			if im.x == x && im.y == y && im.cols == cols && im.rows == rows && sameImage(im.img, img) {
+				im.under = t.cellsUnder(im)
+				return true
+			}
+		}
+	}
+	t.removeImages(x, y, cols, rows)
+	if !t.caps.Sixel || t.inline > 0 {
+		return false
+	}
+	if img == nil {
+		return true
+	}
+	// The image may not reach the last line, as drawing there
+	// could scroll the terminal.
+	cols = min(cols, t.w-x)
+	rows = min(rows, t.h-1-y)
+	if x < 0 || y < 0 || cols < 1 || rows < 1 {
+		return false
+	}
+	if ws, err := t.tty.WindowSize(); err != nil {
+		return false
+	} else if cw, _ := ws.CellDimensions(); cw == 0 {
+		return false
+	}
+	im := &tImage{x: x, y: y, cols: cols, rows: rows, img: img}
+	im.under = t.cellsUnder(im)
+	t.lockImage(im, true)
+	t.images = append(t.images, im)
+	return true
+}
+
+// sameImage reports whether the two are the same image.  Only images
+// held by reference are compared, as others may not be comparable.
+func sameImage(a, b image.Image) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || reflect.ValueOf(a).Kind() != reflect.Ptr {
+		return false
+	}
+	return a == b
+}
+
+func (t *tScreen) cellsUnder(im *tImage) []imageCell {
+	under := make([]imageCell, 0, im.cols*im.rows)
+	for y := im.y; y < im.y+im.rows; y++ {
+		for x := im.x; x < im.x+im.cols; x++ {
+			mainc, combc, style, _ := t.cells.GetContent(x, y)
+			under = append(under, imageCell{mainc: mainc, combc: combc, style: style})
+		}
+	}
+	return under
+}
+
+// imageChanged reports whether the content under the image was changed.
+func (t *tScreen) imageChanged(im *tImage) bool {
+	i := 0
+	for y := im.y; y < im.y+im.rows; y++ {
+		for x := im.x; x < im.x+im.cols; x++ {
+			mainc, combc, style, _ := t.cells.GetContent(x, y)
+			u := &im.under[i]
+			i++
+			if mainc != u.mainc || style != u.style || len(combc) != len(u.combc) {
+				return true
+			}
+			for j := range combc {
+				if combc[j] != u.combc[j] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (t *tScreen) lockImage(im *tImage, lock bool) {
+	for y := im.y; y < im.y+im.rows; y++ {
+		for x := im.x; x < im.x+im.cols; x++ {
+			if lock {
+				t.cells.LockCell(x, y)
+			} else {
+				t.cells.UnlockCell(x, y)
+			}
+		}
+	}
+}
+
+// removeImages removes any images overlapping the region, unlocking
+// the cells so that they will be drawn over them.
+func (t *tScreen) removeImages(x, y, cols, rows int) {
+	keep := t.images[:0]
+	for _, im := range t.images {
+		if im.x < x+cols && x < im.x+im.cols && im.y < y+rows && y < im.y+im.rows {
+			t.lockImage(im, false)
+			continue
+		}
+		keep = append(keep, im)
+	}
+	t.images = keep
+}
+
+// hideImages notes that the images are no longer on the terminal, for
+// example because it was cleared, so that they are drawn again.
+func (t *tScreen) hideImages() {
+	for _, im := range t.images {
+		im.shown = false
+	}
+}
+
+// checkImages is called before drawing cells, and removes images that
+// no longer fit on the screen, or whose cells were changed.  It also
+// (re)encodes images as needed for the current size of the cells.
+func (t *tScreen) checkImages() {
+	if len(t.images) == 0 {
+		return
+	}
+	var cw, ch int
+	if ws, err := t.tty.WindowSize(); err == nil {
+		cw, ch = ws.CellDimensions()
+	}
+	keep := t.images[:0]
+	for _, im := range t.images {
+		if cw == 0 || im.x+im.cols > t.w || im.y+im.rows >= t.h || t.imageChanged(im) {
+			t.lockImage(im, false)
+			continue
+		}
+		if im.cw != cw || im.ch != ch {
+			im.data = encodeSixel(im.img, im.cols*cw, im.rows*ch)
+			im.cw, im.ch = cw, ch
+			im.shown = false
+		}
+		// resizing loses locks
+		t.lockImage(im, true)
+		keep = append(keep, im)
+	}
+	t.images = keep
+}
+
+// drawImages draws any images not already shown.  This is done after
+// the cells are drawn, so that the images are on top.
+func (t *tScreen) drawImages() {
+	for _, im := range t.images {
+		if im.shown {
+			continue
+		}
+		t.gotoXY(im.x, im.y)
+		t.writeString(string(im.data))
+		im.shown = true
+		// the terminal moves the cursor, but where to varies
+		t.cx = -1
+		t.cy = -1
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package tcell
+
+import (
+	"bytes"
+)
+
+// keyTrie is a prefix tree of the byte sequences sent by the terminal for
+// its keys.  It lets input be decoded in time proportional to the length of
+// the sequence, rather than to the number of keys the terminal has.
+type keyTrie struct {
+	code     *tKeyCode
+	labels   []byte // sorted, one for each child
+	children []*keyTrie
+}
+
+// newKeyTrie compiles the key table into a trie.
+func newKeyTrie(keycodes map[string]*tKeyCode) *keyTrie {
+	root := &keyTrie{}
+	for seq, code := range keycodes {
+		root.add(seq, code)
+	}
+	return root
+}
+
+func (n *keyTrie) add(seq string, code *tKeyCode) {
+	for i := 0; i < len(seq); i++ {
+		c := seq[i]
+		j := 0
+		for j < len(n.labels) && n.labels[j] < c {
+			j++
+		}
+		if j == len(n.labels) || n.labels[j] != c {
+			n.labels = append(n.labels, 0)
+			copy(n.labels[j+1:], n.labels[j:])
+			n.labels[j] = c
+			n.children = append(n.children, nil)
+			copy(n.children[j+1:], n.children[j:])
+			n.children[j] = &keyTrie{}
+		}
+		n = n.children[j]
+	}
+	n.code = code
+}
+
+func (n *keyTrie) child(c byte) *keyTrie {
+	if i := bytes.IndexByte(n.labels, c); i >= 0 {
+		return n.children[i]
+	}
+	return nil
+}
+
+// match looks for the longest key sequence at the start of b, returning
+// its key code and length.  Partial is true if all of b is the start of a
+// longer sequence.  Callers should still take a key that matched, as
+// terminals send each key in a single write.
+func (n *keyTrie) match(b []byte) (code *tKeyCode, length int, partial bool) {
+	for i, c := range b {
+		if n = n.child(c); n == nil {
+			return code, length, false
+		}
+		if n.code != nil {
+			code, length = n.code, i+1
+		}
+	}
+	return code, length, len(n.children) > 0
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package tcell
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+)
+
+// kittyImage is an image loaded using the kitty graphics protocol.
+type kittyImage struct {
+	t     *tScreen
+	id    int
+	data  string // commands to transmit the image
+	sent  bool   // true if the terminal holds the image
+	freed bool
+	last  int // last placement id
+}
+
+// kittyPlacement is a placement of a kittyImage.
+type kittyPlacement struct {
+	img        *kittyImage
+	id         int
+	x, y       int
+	cols, rows int
+	z          int
+	shown      bool
+}
+
+// kittyChunk is the most data the protocol allows in a single command.
+const kittyChunk = 4096
+
+// kittyTransmit returns the commands to transmit the PNG encoded image,
+// split into chunks as the protocol requires.  All replies are suppressed.
+func kittyTransmit(id int, data []byte) string {
+	enc := base64.StdEncoding.EncodeToString(data)
+	sb := &strings.Builder{}
+	for first := true; first || len(enc) > 0; first = false {
+		chunk := enc[:min(len(enc), kittyChunk)]
+		enc = enc[len(chunk):]
+		more := 0
+		if len(enc) > 0 {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(sb, "\x1b_Ga=t,f=100,i=%d,q=2,m=%d;%s\x1b\\", id, more, chunk)
+		} else {
+			fmt.Fprintf(sb, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return sb.String()
+}
+
+func (t *tScreen) LoadImage(img image.Image) Image {
+	t.Lock()
+	defer t.Unlock()
+
+	if img == nil || !t.caps.KittyGraphics {
+		return nil
+	}
+	b := &bytes.Buffer{}
+	if err := png.Encode(b, img); err != nil {
+		return nil
+	}
+	t.gfxLast++
+	ki := &kittyImage{t: t, id: t.gfxLast, data: kittyTransmit(t.gfxLast, b.Bytes())}
+	t.gfxImages = append(t.gfxImages, ki)
+	return ki
+}
+
+func (ki *kittyImage) Place(x, y, cols, rows, z int) ImagePlacement {
+	t := ki.t
+	t.Lock()
+	defer t.Unlock()
+
+	if ki.freed || x < 0 || y < 0 || cols < 1 || rows < 1 {
+		return nil
+	}
+	ki.last++
+	p := &kittyPlacement{img: ki, id: ki.last, x: x, y: y, cols: cols, rows: rows, z: z}
+	t.placements = append(t.placements, p)
+	t.lockPlacement(p, true)
+	return p
+}
+
+func (ki *kittyImage) Free() {
+	t := ki.t
+	t.Lock()
+	defer t.Unlock()
+
+	if ki.freed {
+		return
+	}
+	ki.freed = true
+	t.removePlacements(func(p *kittyPlacement) bool { return p.img == ki })
+	for i, other := range t.gfxImages {
+		if other == ki {
+			t.gfxImages = append(t.gfxImages[:i], t.gfxImages[i+1:]...)
+			break
+		}
+	}
+	if ki.sent {
+		// this also deletes the placements
+		t.gfxPending = append(t.gfxPending, fmt.Sprintf("\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", ki.id))
+		ki.sent = false
+	}
+}
+
+func (p *kittyPlacement) Delete() {
+	t := p.img.t
+	t.Lock()
+	defer t.Unlock()
+
+	if t.removePlacements(func(other *kittyPlacement) bool { return other == p }) && p.shown {
+		t.gfxPending = append(t.gfxPending,
+			fmt.Sprintf("\x1b_Ga=d,d=i,i=%d,p=%d,q=2\x1b\\", p.img.id, p.id))
+	}
+	p.shown = false
+}
+
+// removePlacements removes the placements matching the function, and
+// reports whether there were any.
+func (t *tScreen) removePlacements(match func(*kittyPlacement) bool) bool {
+	found := false
+	keep := t.placements[:0]
+	for _, p := range t.placements {
+		if match(p) {
+			t.lockPlacement(p, false)
+			found = true
+			continue
+		}
+		keep = append(keep, p)
+	}
+	t.placements = keep
+	if found {
+		// placements may overlap, so restore the locks of the others
+		t.lockPlacements()
+	}
+	return found
+}
+
+// lockPlacement locks (or unlocks) the cells under a placement drawn
+// above the text.  Placements below the text leave them alone.
+func (t *tScreen) lockPlacement(p *kittyPlacement, lock bool) {
+	if p.z < 0 {
+		return
+	}
+	for y := p.y; y < p.y+p.rows; y++ {
+		for x := p.x; x < p.x+p.cols; x++ {
+			if lock {
+				t.cells.LockCell(x, y)
+			} else {
+				t.cells.UnlockCell(x, y)
+			}
+		}
+	}
+}
+
+// lockPlacements locks the cells under all placements.  This is needed
+// before drawing, as resizing loses locks.
+func (t *tScreen) lockPlacements() {
+	for _, p := range t.placements {
+		t.lockPlacement(p, true)
+	}
+}
+
+// hidePlacements removes all of our placements from the terminal, for
+// example because it was cleared, so that they are placed again.
+func (t *tScreen) hidePlacements() {
+	shown := false
+	for _, p := range t.placements {
+		shown = shown || p.shown
+		p.shown = false
+	}
+	if shown {
+		t.writeString("\x1b_Ga=d,d=a,q=2\x1b\\")
+	}
+}
+
+// drawPlacements sends any pending deletions, and then shows placements
+// not already shown, transmitting their images first if needed.
+func (t *tScreen) drawPlacements() {
+	for _, s := range t.gfxPending {
+		t.writeString(s)
+	}
+	t.gfxPending = nil
+	for _, p := range t.placements {
+		if p.shown || p.x >= t.w || p.y >= t.h {
+			continue
+		}
+		if !p.img.sent {
+			t.writeString(p.img.data)
+			p.img.sent = true
+		}
+		t.gotoXY(p.x, p.y)
+		t.writeString(fmt.Sprintf("\x1b_Ga=p,i=%d,p=%d,c=%d,r=%d,z=%d,C=1,q=2\x1b\\",
+			p.img.id, p.id, p.cols, p.rows, p.z))
+		p.shown = true
+	}
+}
+
+// freeImages releases all images held by the terminal, which may not keep
+// them while we are suspended.  They are transmitted again when needed.
+func (t *tScreen) freeImages() {
+	for _, ki := range t.gfxImages {
+		if ki.sent {
+			t.TPuts(fmt.Sprintf("\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", ki.id))
+			ki.sent = false
+		}
+	}
+	for _, p := range t.placements {
+		p.shown = false
+	}
+	t.gfxPending = nil
+}
@@ -0,0 +1,1417 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm) && !windows
+// +build !js !wasm
+// +build !windows
+
+package tcell
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockTty is a Tty that lets tests feed input, and inspect output.
+type mockTty struct {
+	in      chan []byte
+	pending []byte
+	out     bytes.Buffer
+	stop    chan struct{}
+	ws      WindowSize
+	cb      func()
+	sync.Mutex
+}
+
+func newMockTty(w, h int) *mockTty {
+	return &mockTty{
+		in: make(chan []byte, 16),
+		ws: WindowSize{Width: w, Height: h},
+	}
+}
+
+func (m *mockTty) Start() error {
+	m.Lock()
+	m.stop = make(chan struct{})
+	m.Unlock()
+	return nil
+}
+
+func (m *mockTty) Stop() error {
+	return nil
+}
+
+func (m *mockTty) Drain() error {
+	m.Lock()
+	select {
+	case <-m.stop:
+	default:
+		close(m.stop)
+	}
+	m.Unlock()
+	return nil
+}
+
+func (m *mockTty) NotifyResize(cb func()) {
+	m.Lock()
+	m.cb = cb
+	m.Unlock()
+}
+
+func (m *mockTty) WindowSize() (WindowSize, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.ws, nil
+}
+
+func (m *mockTty) Read(b []byte) (int, error) {
+	m.Lock()
+	stop := m.stop
+	m.Unlock()
+	if len(m.pending) == 0 {
+		select {
+		case m.pending = <-m.in:
+		case <-stop:
+			return 0, io.EOF
+		}
+	}
+	n := copy(b, m.pending)
+	m.pending = m.pending[n:]
+	return n, nil
+}
+
+func (m *mockTty) Write(b []byte) (int, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.out.Write(b)
+}
+
+func (m *mockTty) Close() error {
+	return nil
+}
+
+// Output returns everything written to the tty since the last call.
+func (m *mockTty) Output() string {
+	m.Lock()
+	defer m.Unlock()
+	s := m.out.String()
+	m.out.Reset()
+	return s
+}
+
+// Input feeds bytes to the screen as if typed at the terminal.
+func (m *mockTty) Input(s string) {
+	m.in <- []byte(s)
+}
+
+func mkTermScreen(t testing.TB, term string) (Screen, *mockTty) {
+	t.Helper()
+	ti, err := LookupTerminfo(term)
+	if err != nil {
+		t.Fatalf("Failed to find terminfo %s: %v", term, err)
+	}
+	tc := *ti
+	tty := newMockTty(80, 24)
+	s, err := NewTerminfoScreenFromTtyTerminfo(tty, &tc)
+	if err != nil {
+		t.Fatalf("Failed to get screen: %v", err)
+	}
+	if err = s.Init(); err != nil {
+		t.Fatalf("Failed to initialize screen: %v", err)
+	}
+	return s, tty
+}
+
+// nextEvent returns the next event that is not a resize.
+func nextEvent(t *testing.T, s Screen) Event {
+	t.Helper()
+	evch := make(chan Event, 1)
+	go func() {
+		for {
+			ev := s.PollEvent()
+			if _, ok := ev.(*EventResize); ok {
+				continue
+			}
+			evch <- ev
+			return
+		}
+	}()
+	select {
+	case ev := <-evch:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatalf("Timeout waiting for event")
+	}
+	return nil
+}
+
+// waitOutput waits for the given string to be written to the tty.
+func waitOutput(t *testing.T, tty *mockTty, want string) {
+	t.Helper()
+	var got string
+	for i := 0; i < 100; i++ {
+		got += tty.Output()
+		if strings.Contains(got, want) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Output %q does not contain %q", got, want)
+}
+
+func checkKey(t *testing.T, ev Event, key Key, ch rune, mod ModMask, et KeyEventType) {
+	t.Helper()
+	ek, ok := ev.(*EventKey)
+	if !ok {
+		t.Fatalf("Expected key event, got %T", ev)
+	}
+	if ek.Key() != key || ek.Modifiers() != mod || ek.EventType() != et {
+		t.Errorf("Bad key event: %s (type %d)", ek.Name(), ek.EventType())
+	}
+	if key == KeyRune && ek.Rune() != ch {
+		t.Errorf("Bad rune: %q != %q", ek.Rune(), ch)
+	}
+}
+
+func TestKittyKeyboard(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	waitOutput(t, tty, "\x1b[?u\x1b[c")
+	tty.Input("\x1b[?0u\x1b[?62;22c")
+	waitOutput(t, tty, "\x1b[>1u")
+
+	tty.Input("\x1b[105;5u")
+	checkKey(t, nextEvent(t, s), KeyCtrlI, 0, ModCtrl, KeyEventPress)
+	tty.Input("\x1b[9u")
+	checkKey(t, nextEvent(t, s), KeyTab, 0, ModNone, KeyEventPress)
+	tty.Input("\x1b[27u")
+	checkKey(t, nextEvent(t, s), KeyEsc, 0, ModNone, KeyEventPress)
+	tty.Input("\x1b[97;9u")
+	checkKey(t, nextEvent(t, s), KeyRune, 'a', ModSuper, KeyEventPress)
+	tty.Input("\x1b[1;5:1A")
+	checkKey(t, nextEvent(t, s), KeyUp, 0, ModCtrl, KeyEventPress)
+	tty.Input("\x1b[13;3~")
+	checkKey(t, nextEvent(t, s), KeyF3, 0, ModAlt, KeyEventPress)
+
+	// release events are not reported unless requested
+	tty.Input("\x1b[97;1:3u\x1b[98u")
+	checkKey(t, nextEvent(t, s), KeyRune, 'b', ModNone, KeyEventPress)
+
+	s.SetKeyboardFlags(KeyRepeatEvents | KeyReleaseEvents)
+	waitOutput(t, tty, "\x1b[=27;1u")
+	tty.Input("\x1b[97;2:2;65u")
+	checkKey(t, nextEvent(t, s), KeyRune, 'A', ModShift, KeyEventRepeat)
+	tty.Input("\x1b[97;1:3u")
+	checkKey(t, nextEvent(t, s), KeyRune, 'a', ModNone, KeyEventRelease)
+
+	s.Fini()
+	waitOutput(t, tty, "\x1b[<u")
+}
+
+func TestKittyKeyboardUnsupported(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	waitOutput(t, tty, "\x1b[?u\x1b[c")
+	tty.Input("\x1b[?62;22c")
+	tty.Input("\x1b[1;5A")
+	checkKey(t, nextEvent(t, s), KeyUp, 0, ModCtrl, KeyEventPress)
+	if out := tty.Output(); strings.Contains(out, "\x1b[>") {
+		t.Errorf("Kitty flags pushed to unsupported terminal: %q", out)
+	}
+}
+
+func TestSyncOutput(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	waitOutput(t, tty, "\x1b[?2026$p")
+	s.SetContent(0, 0, 'A', nil, StyleDefault)
+	s.Show()
+	if out := tty.Output(); strings.Contains(out, "\x1b[?2026h") {
+		t.Errorf("Synchronized output used before reply: %q", out)
+	}
+
+	tty.Input("\x1b[?2026;2$yx")
+	checkKey(t, nextEvent(t, s), KeyRune, 'x', ModNone, KeyEventPress)
+	s.SetContent(0, 0, 'B', nil, StyleDefault)
+	s.Show()
+	out := tty.Output()
+	if !strings.HasPrefix(out, "\x1b[?2026h") || !strings.HasSuffix(out, "\x1b[?2026l") {
+		t.Errorf("Draw not synchronized: %q", out)
+	}
+}
+
+func TestSyncOutputUnsupported(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	waitOutput(t, tty, "\x1b[?2026$p")
+	tty.Input("\x1b[?2026;0$yx")
+	checkKey(t, nextEvent(t, s), KeyRune, 'x', ModNone, KeyEventPress)
+	s.Sync()
+	if out := tty.Output(); strings.Contains(out, "\x1b[?2026h") {
+		t.Errorf("Synchronized output used when unsupported: %q", out)
+	}
+}
+
+func TestSyncOutputOption(t *testing.T) {
+	ti, err := LookupTerminfo("xterm-256color")
+	if err != nil {
+		t.Fatalf("Failed to find terminfo: %v", err)
+	}
+	for _, on := range []bool{false, true} {
+		tc := *ti
+		tty := newMockTty(80, 24)
+		s, err := NewTerminfoScreenWithOptions(tty, &tc, WithSyncOutput(on))
+		if err != nil {
+			t.Fatalf("Failed to get screen: %v", err)
+		}
+		if err = s.Init(); err != nil {
+			t.Fatalf("Failed to initialize screen: %v", err)
+		}
+		s.SetContent(0, 0, 'A', nil, StyleDefault)
+		s.Show()
+		out := tty.Output()
+		if strings.Contains(out, "\x1b[?2026$p") {
+			t.Errorf("Synchronized output queried: %q", out)
+		}
+		if strings.Contains(out, "\x1b[?2026h") != on {
+			t.Errorf("Synchronized output %v: %q", on, out)
+		}
+		s.Fini()
+	}
+}
+
+func TestGraphemeMode(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+
+	waitOutput(t, tty, "\x1b[?2027$p")
+	tty.Input("\x1b[?2027;2$yx")
+	checkKey(t, nextEvent(t, s), KeyRune, 'x', ModNone, KeyEventPress)
+	waitOutput(t, tty, "\x1b[?2027h")
+	if !s.Capabilities().Graphemes {
+		t.Errorf("Grapheme clustering not reported")
+	}
+
+	s.PutStr(0, 0, "\U0001F1EF\U0001F1F5x")
+	s.Show()
+	if out := tty.Output(); !strings.Contains(out, "\U0001F1EF\U0001F1F5\x1b[1;3Hx") {
+		t.Errorf("Bad output: %q", out)
+	}
+
+	s.Fini()
+	if out := tty.Output(); !strings.Contains(out, "\x1b[?2027l") {
+		t.Errorf("Grapheme cluster mode not reset: %q", out)
+	}
+}
+
+func TestScrollRegion(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	show := func(lines []string) string {
+		for y, l := range lines {
+			for x := 0; x < 80; x++ {
+				r := ' '
+				if x < len(l) {
+					r = rune(l[x])
+				}
+				s.SetContent(x, y, r, nil, StyleDefault)
+			}
+		}
+		s.Show()
+		return tty.Output()
+	}
+
+	lines := make([]string, 24)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	show(lines)
+
+	// scroll the whole screen up by one line
+	lines = append(lines[1:], "line 24")
+	out := show(lines)
+	if !strings.Contains(out, "\x1b[24;1H\n") {
+		t.Errorf("Screen not scrolled: %q", out)
+	}
+	if strings.Contains(out, "line 5") || !strings.Contains(out, "line 24") {
+		t.Errorf("Wrong lines redrawn: %q", out)
+	}
+
+	// scroll part of the screen down by two lines
+	lines = append(lines[:2], append([]string{"new a", "new b"}, lines[2:20]...)...)
+	lines = append(lines, "footer 1", "footer 2")
+	out = show(lines)
+	if !strings.Contains(out, "\x1b[3;22r") || !strings.Contains(out, "\x1b[3;1H\x1b[2T\x1b[1;24r") {
+		t.Errorf("Region not scrolled: %q", out)
+	}
+	if strings.Contains(out, "line 10") || !strings.Contains(out, "new b") {
+		t.Errorf("Wrong lines redrawn: %q", out)
+	}
+}
+
+func TestScrollRegionVT100(t *testing.T) {
+	// not like XTerm, but the terminfo entry says it can scroll
+	s, tty := mkTermScreen(t, "vt100")
+	defer s.Fini()
+
+	show := func(lines []string) string {
+		for y, l := range lines {
+			for x := 0; x < 80; x++ {
+				r := ' '
+				if x < len(l) {
+					r = rune(l[x])
+				}
+				s.SetContent(x, y, r, nil, StyleDefault)
+			}
+		}
+		s.Show()
+		return tty.Output()
+	}
+
+	lines := make([]string, 24)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	show(lines)
+
+	// there is no SD, so the region is scrolled a line at a time
+	lines = append(lines[:2], append([]string{"new a", "new b"}, lines[2:22]...)...)
+	out := show(lines)
+	if !strings.Contains(out, "\x1b[3;24r") || !strings.Contains(out, "\x1b[3;1H\x1bM\x1bM") {
+		t.Errorf("Region not scrolled: %q", out)
+	}
+	if strings.Contains(out, "\x1b[2T") || strings.Contains(out, "line 10") {
+		t.Errorf("Wrong scroll: %q", out)
+	}
+}
+
+func TestEraseRepeat(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	s.Show()
+	tty.Output()
+
+	// a run of repeated characters
+	for x := 0; x < 30; x++ {
+		s.SetContent(x, 0, 'x', nil, StyleDefault)
+	}
+	for x := 0; x < 80; x++ {
+		s.SetContent(x, 1, 'y', nil, StyleDefault.Bold(true))
+	}
+	s.Show()
+	out := tty.Output()
+	if !strings.Contains(out, "x\x1b[29b") || !strings.Contains(out, "y\x1b[79b") {
+		t.Errorf("Repeat not used: %q", out)
+	}
+
+	// now clear them again
+	for x := 0; x < 80; x++ {
+		s.SetContent(x, 0, ' ', nil, StyleDefault)
+		s.SetContent(x, 1, ' ', nil, StyleDefault)
+	}
+	s.SetContent(79, 1, '|', nil, StyleDefault)
+	s.Show()
+	out = tty.Output()
+	if !strings.Contains(out, "\x1b[K") {
+		t.Errorf("Erase to end of line not used: %q", out)
+	}
+	if !strings.Contains(out, "\x1b[79X") {
+		t.Errorf("Erase characters not used: %q", out)
+	}
+
+	// styles that erasing can't reproduce must be drawn
+	st := StyleDefault.Reverse(true)
+	for x := 0; x < 80; x++ {
+		s.SetContent(x, 2, ' ', nil, st)
+	}
+	s.Show()
+	out = tty.Output()
+	if strings.Contains(out, "\x1b[K") || !strings.Contains(out, strings.Repeat(" ", 80)) {
+		t.Errorf("Reverse blanks erased: %q", out)
+	}
+}
+
+func TestEraseNoRepeat(t *testing.T) {
+	// rxvt has no REP, even though it is like XTerm
+	s, tty := mkTermScreen(t, "rxvt")
+	defer s.Fini()
+
+	s.Show()
+	tty.Output()
+
+	for x := 0; x < 30; x++ {
+		s.SetContent(x, 0, 'x', nil, StyleDefault)
+	}
+	s.Show()
+	out := tty.Output()
+	if strings.Contains(out, "\x1b[29b") || !strings.Contains(out, strings.Repeat("x", 30)) {
+		t.Errorf("Repeat used: %q", out)
+	}
+}
+
+func TestInlineScreen(t *testing.T) {
+	ti, err := LookupTerminfo("xterm-256color")
+	if err != nil {
+		t.Fatalf("Failed to find terminfo: %v", err)
+	}
+	tc := *ti
+	tty := newMockTty(80, 24)
+	s, err := NewTerminfoScreenWithOptions(tty, &tc, WithInline(5))
+	if err != nil {
+		t.Fatalf("Failed to get screen: %v", err)
+	}
+	if err = s.Init(); err != nil {
+		t.Fatalf("Failed to initialize screen: %v", err)
+	}
+	defer s.Fini()
+
+	out := tty.Output()
+	if !strings.Contains(out, "\r\n\n\n\n\x1b[4A") {
+		t.Errorf("No room made for screen: %q", out)
+	}
+	if strings.Contains(out, "\x1b[?1049h") || strings.Contains(out, tc.Clear) {
+		t.Errorf("Terminal taken over: %q", out)
+	}
+	if w, h := s.Size(); w != 80 || h != 5 {
+		t.Errorf("Wrong size: %dx%d", w, h)
+	}
+
+	// mouse positions are relative to the screen, wherever it is
+	if !strings.Contains(out, "\x1b[6n") {
+		t.Errorf("Screen position not queried: %q", out)
+	}
+	tty.Input("\x1b[11;1R")
+	s.EnableMouse()
+	tty.Input("\x1b[<0;4;13M")
+	if ev, ok := nextEvent(t, s).(*EventMouse); !ok {
+		t.Errorf("Expected mouse event")
+	} else if x, y := ev.Position(); x != 3 || y != 2 {
+		t.Errorf("Bad position: %d, %d", x, y)
+	}
+
+	s.SetContent(3, 2, 'X', nil, StyleDefault)
+	s.Show()
+	tty.Output()
+	s.SetContent(5, 1, 'Y', nil, StyleDefault)
+	s.Show()
+	out = tty.Output()
+	if !strings.Contains(out, "\r\x1b[3A\x1b[5C\x1b(B\x1b[mY") {
+		t.Errorf("Not relative addressing: %q", out)
+	}
+
+	s.PrintAbove("hello\nworld")
+	s.Show()
+	out = tty.Output()
+	if !strings.Contains(out, "\r\x1b[1A") || !strings.Contains(out, "\x1b[Jhello\r\nworld\r\n\r\n\n\n\n\x1b[4A") {
+		t.Errorf("Lines not printed above: %q", out)
+	}
+	if !strings.Contains(out, "X") || !strings.Contains(out, "Y") {
+		t.Errorf("Screen not redrawn: %q", out)
+	}
+
+	s.Fini()
+	out = tty.Output()
+	if !strings.HasSuffix(strings.Split(out, "\x1b[?1000l")[0], "\r\n") {
+		t.Errorf("Cursor not left below screen: %q", out)
+	}
+	if strings.Contains(out, "\x1b[?1049l") || strings.Contains(out, tc.Clear) {
+		t.Errorf("Last frame not left on terminal: %q", out)
+	}
+}
+
+func TestProbeCapabilities(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	waitOutput(t, tty, "\x1b[c")
+	tty.Input("\x1b[?62;22c")
+
+	capch := make(chan Capabilities, 1)
+	go func() {
+		capch <- s.ProbeCapabilities(time.Second)
+	}()
+	waitOutput(t, tty, probeQuery)
+	tty.Input("\x1bP>|kitty(0.31.0)\x1b\\\x1b[>1;4000;19c")
+	tty.Input("\x1b[?1004;2$y\x1b[?2004;0$y\x1b[?2026;2$y")
+	tty.Input("\x1bP1+r524742\x1b\\\x1b[?62;4;22cz")
+	caps := <-capch
+
+	want := Capabilities{
+		Sixel:          true,
+		TrueColor:      true,
+		SyncOutput:     true,
+		Focus:          true,
+		BracketedPaste: false,
+		Name:           "kitty",
+		Version:        "0.31.0",
+		Probed:         true,
+	}
+	if caps != want {
+		t.Errorf("Wrong capabilities: %+v", caps)
+	}
+	// none of the replies should show up as keys
+	checkKey(t, nextEvent(t, s), KeyRune, 'z', ModNone, KeyEventPress)
+}
+
+func TestProbeCapabilitiesTimeout(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	caps := s.ProbeCapabilities(50 * time.Millisecond)
+	waitOutput(t, tty, probeQuery)
+	// the terminal database entry does not tell us the program
+	if caps.Probed || caps.Name != "" || caps.Sixel {
+		t.Errorf("Wrong capabilities: %+v", caps)
+	}
+}
+
+func TestColorScheme(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	s.GetColorScheme()
+	waitOutput(t, tty, "\x1b]10;?\x1b\\\x1b]11;?\x1b\\")
+	tty.Input("\x1b]10;rgb:ffff/ffff/ffff\x1b\\\x1b]11;rgb:1c1c/1c1c/1c1c\a")
+	ev, ok := nextEvent(t, s).(*EventColorScheme)
+	if !ok {
+		t.Fatalf("Expected color scheme event")
+	}
+	if ev.Scheme() != ColorSchemeDark || ev.Foreground() != NewRGBColor(255, 255, 255) || ev.Background() != NewRGBColor(28, 28, 28) {
+		t.Errorf("Wrong colors: %v %v %v", ev.Scheme(), ev.Foreground(), ev.Background())
+	}
+
+	s.EnableColorScheme()
+	waitOutput(t, tty, "\x1b[?2031h")
+	tty.Input("\x1b[?997;2n")
+	waitOutput(t, tty, "\x1b]10;?\x1b\\\x1b]11;?\x1b\\")
+	tty.Input("\x1b]10;rgb:00/00/00\x1b\\\x1b]11;#eeeeee\x1b\\")
+	ev, ok = nextEvent(t, s).(*EventColorScheme)
+	if !ok {
+		t.Fatalf("Expected color scheme event")
+	}
+	if ev.Scheme() != ColorSchemeLight || ev.Foreground() != NewRGBColor(0, 0, 0) || ev.Background() != NewRGBColor(238, 238, 238) {
+		t.Errorf("Wrong colors: %v %v %v", ev.Scheme(), ev.Foreground(), ev.Background())
+	}
+
+	s.Fini()
+	waitOutput(t, tty, "\x1b[?2031l")
+}
+
+func TestPalette(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	s.SetPaletteColor(1, NewRGBColor(10, 20, 30))
+	waitOutput(t, tty, "\x1b]4;1;rgb:0a/14/1e\x1b\\")
+
+	s.QueryPaletteColor(2)
+	waitOutput(t, tty, "\x1b]4;2;?\x1b\\")
+	tty.Input("\x1b]4;2;rgb:0000/8080/0000\x1b\\")
+	ev, ok := nextEvent(t, s).(*EventPaletteColor)
+	if !ok {
+		t.Fatalf("Expected palette color event")
+	}
+	if ev.Index() != 2 || ev.Color() != NewRGBColor(0, 128, 0) {
+		t.Errorf("Wrong palette color: %d %v", ev.Index(), ev.Color())
+	}
+
+	s.ResetPalette()
+	waitOutput(t, tty, "\x1b]104\x1b\\")
+	// even if we didn't change it ourselves
+	s.ResetPalette()
+	waitOutput(t, tty, "\x1b]104\x1b\\")
+
+	s.SetPaletteColor(1, NewRGBColor(10, 20, 30))
+	s.Fini()
+	waitOutput(t, tty, "\x1b]104\x1b\\")
+}
+
+func TestDrawImage(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	img := image.NewRGBA(image.Rect(0, 0, 20, 40))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: 255, A: 255}), image.Point{}, draw.Src)
+
+	if s.DrawImage(2, 1, 2, 2, img) {
+		t.Errorf("Image drawn without sixel support")
+	}
+	tty.Lock()
+	tty.ws.PixelWidth, tty.ws.PixelHeight = 800, 480
+	tty.Unlock()
+	waitOutput(t, tty, "\x1b[c")
+	tty.Input("\x1b[?62;4;22c")
+	for i := 0; !s.Capabilities().Sixel; i++ {
+		if i > 100 {
+			t.Fatalf("No sixel support detected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !s.DrawImage(2, 1, 2, 2, img) {
+		t.Fatalf("Image not drawn")
+	}
+	s.Show()
+	out := tty.Output()
+	if !strings.Contains(out, "\x1b[2;3H\x1bP0;1;0q\"1;1;20;40") {
+		t.Errorf("Image not sent: %q", out)
+	}
+
+	// drawing it again is a no-op
+	s.DrawImage(2, 1, 2, 2, img)
+	s.Show()
+	if out = tty.Output(); strings.Contains(out, "\x1bP") {
+		t.Errorf("Image sent again: %q", out)
+	}
+
+	// but a full redraw sends it again
+	s.Sync()
+	if out = tty.Output(); !strings.Contains(out, "\x1bP") {
+		t.Errorf("Image not sent again: %q", out)
+	}
+
+	// changing the content under it removes it
+	s.SetContent(3, 2, 'x', nil, StyleDefault)
+	s.Show()
+	out = tty.Output()
+	if strings.Contains(out, "\x1bP") || !strings.Contains(out, "\x1b[2;3H") || !strings.Contains(out, "x") {
+		t.Errorf("Image not removed: %q", out)
+	}
+}
+
+func TestKittyGraphics(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	if s.LoadImage(img) != nil {
+		t.Errorf("Image loaded without kitty graphics support")
+	}
+	waitOutput(t, tty, "\x1b_Gi=31,s=1,v=1,a=q,t=d,f=24;AAAA\x1b\\")
+	tty.Input("\x1b_Gi=31;OK\x1b\\\x1b[?62;22c")
+	for i := 0; !s.Capabilities().KittyGraphics; i++ {
+		if i > 100 {
+			t.Fatalf("No kitty graphics support detected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// the reply must not be reported as keys
+	tty.Input("z")
+	checkKey(t, nextEvent(t, s), KeyRune, 'z', ModNone, KeyEventPress)
+
+	im := s.LoadImage(img)
+	if im == nil {
+		t.Fatalf("Image not loaded")
+	}
+	above := im.Place(2, 1, 3, 2, 0)
+	below := im.Place(0, 3, 2, 1, -1)
+	s.Show()
+	out := tty.Output()
+	if strings.Count(out, "\x1b_Ga=t,f=100,i=1,q=2,m=0;") != 1 {
+		t.Errorf("Image not sent once: %q", out)
+	}
+	if !strings.Contains(out, "\x1b[2;3H\x1b_Ga=p,i=1,p=1,c=3,r=2,z=0,C=1,q=2\x1b\\") {
+		t.Errorf("Image not placed above text: %q", out)
+	}
+	if !strings.Contains(out, "\x1b[4;1H\x1b_Ga=p,i=1,p=2,c=2,r=1,z=-1,C=1,q=2\x1b\\") {
+		t.Errorf("Image not placed below text: %q", out)
+	}
+
+	// cells above the text are locked, those below it are not
+	s.SetContent(3, 2, 'x', nil, StyleDefault)
+	s.SetContent(1, 3, 'y', nil, StyleDefault)
+	s.Show()
+	out = tty.Output()
+	if strings.Contains(out, "x") || !strings.Contains(out, "y") || strings.Contains(out, "\x1b_G") {
+		t.Errorf("Bad output: %q", out)
+	}
+
+	above.Delete()
+	s.Show()
+	out = tty.Output()
+	if !strings.Contains(out, "\x1b_Ga=d,d=i,i=1,p=1,q=2\x1b\\") || !strings.Contains(out, "x") {
+		t.Errorf("Placement not deleted: %q", out)
+	}
+
+	// placing it again does not send the image again
+	im.Place(5, 5, 1, 1, 1)
+	s.Show()
+	out = tty.Output()
+	if strings.Contains(out, "a=t") || !strings.Contains(out, "\x1b[6;6H\x1b_Ga=p,i=1,p=3,c=1,r=1,z=1,C=1,q=2\x1b\\") {
+		t.Errorf("Bad output: %q", out)
+	}
+
+	// a full redraw places everything again
+	s.Sync()
+	out = tty.Output()
+	if !strings.Contains(out, "\x1b_Ga=d,d=a,q=2\x1b\\") || strings.Count(out, "a=p,") != 2 {
+		t.Errorf("Bad output: %q", out)
+	}
+
+	below.Delete()
+	s.Fini()
+	out = tty.Output()
+	if !strings.Contains(out, "\x1b_Ga=d,d=I,i=1,q=2\x1b\\") {
+		t.Errorf("Image not freed: %q", out)
+	}
+}
+
+func TestProbeWidths(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	wpch := make(chan WidthPolicy)
+	go func() {
+		wpch <- s.ProbeWidths(time.Second)
+	}()
+	waitOutput(t, tty, "\x1b[1;1H\u2500\x1b[6n")
+	tty.Input("\x1b[1;3R")
+	if wp := <-wpch; !wp.AmbiguousWide {
+		t.Errorf("Ambiguous width not detected as wide")
+	}
+	s.SetContent(0, 1, '\u2500', nil, StyleDefault)
+	if _, _, _, width := s.GetContent(0, 1); width != 2 {
+		t.Errorf("Ambiguous width not wide: %d", width)
+	}
+
+	// without a reply, the policy is left alone
+	if wp := s.ProbeWidths(50 * time.Millisecond); !wp.AmbiguousWide {
+		t.Errorf("Width policy changed")
+	}
+}
+
+func TestCursorPosition(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	s.QueryCursorPosition()
+	waitOutput(t, tty, "\x1b[6n")
+	// this is also Ctrl-F3, but we are expecting a report
+	tty.Input("\x1b[1;5R")
+	ev, ok := nextEvent(t, s).(*EventCursorPosition)
+	if !ok {
+		t.Fatalf("Expected cursor position event")
+	}
+	if x, y := ev.Position(); x != 4 || y != 0 {
+		t.Errorf("Bad position: %d, %d", x, y)
+	}
+
+	// and now we are not
+	tty.Input("\x1b[1;5R")
+	checkKey(t, nextEvent(t, s), KeyF3, 0, ModCtrl, KeyEventPress)
+
+	posch := make(chan [2]int)
+	go func() {
+		x, y, ok := s.GetCursorPosition(time.Second)
+		if !ok {
+			x, y = -1, -1
+		}
+		posch <- [2]int{x, y}
+	}()
+	waitOutput(t, tty, "\x1b[6n")
+	tty.Input("\x1b[5;10R")
+	if pos := <-posch; pos[0] != 9 || pos[1] != 4 {
+		t.Errorf("Bad position: %d, %d", pos[0], pos[1])
+	}
+
+	if _, _, ok := s.GetCursorPosition(10 * time.Millisecond); ok {
+		t.Errorf("Position reported without reply")
+	}
+	// a late reply is not taken for a key
+	tty.Input("\x1b[1;2Rx")
+	checkKey(t, nextEvent(t, s), KeyRune, 'x', ModNone, KeyEventPress)
+
+	// a terminal that never replies does not leave us waiting forever
+	s.QueryCursorPosition()
+	waitOutput(t, tty, "\x1b[6n")
+	time.Sleep(postQueryTimeout + 100*time.Millisecond)
+	tty.Input("\x1b[1;2R")
+	checkKey(t, nextEvent(t, s), KeyF3, 0, ModShift, KeyEventPress)
+}
+
+func TestQuery(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	resch := make(chan result)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		data, err := s.Query(ctx, "\x1b[>0q", MatchDCS(">|"))
+		resch <- result{data, err}
+	}()
+	waitOutput(t, tty, "\x1b[>0q")
+	tty.Input("\x1bP>|term(1.0)\x1b\\")
+	if res := <-resch; res.err != nil || string(res.data) != ">|term(1.0)" {
+		t.Errorf("Bad reply: %q %v", res.data, res.err)
+	}
+
+	s.PostQuery("\x1b]11;?\x1b\\", MatchOSC("11;"))
+	waitOutput(t, tty, "\x1b]11;?\x1b\\")
+	tty.Input("\x1b]11;rgb:0000/0000/0000\x1b\\")
+	if ev, ok := nextEvent(t, s).(*EventReply); !ok {
+		t.Errorf("Expected reply event")
+	} else if ev.Kind() != ReplyOSC || string(ev.Data()) != "11;rgb:0000/0000/0000" {
+		t.Errorf("Bad reply: %d %q", ev.Kind(), ev.Data())
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		data, err := s.GetClipboardContext(ctx)
+		resch <- result{data, err}
+	}()
+	waitOutput(t, tty, "\x1b]52;c;?")
+	tty.Input("\x1b]52;c;aGVsbG8=\x1b\\")
+	if res := <-resch; res.err != nil || string(res.data) != "hello" {
+		t.Errorf("Bad clipboard: %q %v", res.data, res.err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Query(ctx, "\x1b[5n", MatchCSI("", 'n')); err != context.DeadlineExceeded {
+		t.Errorf("Bad error: %v", err)
+	}
+	// a late reply is not taken for input
+	tty.Input("\x1b[0nx")
+	checkKey(t, nextEvent(t, s), KeyRune, 'x', ModNone, KeyEventPress)
+}
+
+func TestRawSequences(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	for _, seq := range []string{
+		"\x1b[1;2:3~", "\x1b]777;notify;hi\a", "\x1bPxyz\x1b\\", "\x1bO5z",
+		// a C1 ST, but not the end of a UTF-8 character
+		"\x1b]2;ś Ŝ ├\x1b\\", "\x1bPś x\x9c",
+	} {
+		tty.Input(seq)
+		ev, ok := nextEvent(t, s).(*EventRaw)
+		if !ok {
+			t.Fatalf("Expected raw event for %q", seq)
+		}
+		if string(ev.Data()) != seq {
+			t.Errorf("Bad raw data: %q, expected %q", ev.Data(), seq)
+		}
+	}
+
+	// SS3 without parameters is just keys, if we don't know it
+	tty.Input("\x1bO}")
+	checkKey(t, nextEvent(t, s), KeyRune, 'O', ModAlt, KeyEventPress)
+	checkKey(t, nextEvent(t, s), KeyRune, '}', ModNone, KeyEventPress)
+
+	tty.Input("\x1b[A")
+	ev := nextEvent(t, s)
+	checkKey(t, ev, KeyUp, 0, ModNone, KeyEventPress)
+	if raw := ev.(*EventKey).Raw(); string(raw) != "\x1b[A" {
+		t.Errorf("Bad raw key: %q", raw)
+	}
+	tty.Input("\x1bx")
+	ev = nextEvent(t, s)
+	checkKey(t, ev, KeyRune, 'x', ModAlt, KeyEventPress)
+	if raw := ev.(*EventKey).Raw(); string(raw) != "\x1bx" {
+		t.Errorf("Bad raw key: %q", raw)
+	}
+	tty.Input("é")
+	ev = nextEvent(t, s)
+	checkKey(t, ev, KeyRune, 'é', ModNone, KeyEventPress)
+	if raw := ev.(*EventKey).Raw(); string(raw) != "é" {
+		t.Errorf("Bad raw key: %q", raw)
+	}
+}
+
+func TestKeyTrie(t *testing.T) {
+	keys := newKeyTrie(map[string]*tKeyCode{
+		"\x1b[2":   {key: KeyF1},
+		"\x1b[2~":  {key: KeyInsert},
+		"\x1b[24~": {key: KeyF12},
+		"\x1bOA":   {key: KeyUp},
+	})
+	cases := []struct {
+		in      string
+		key     Key
+		n       int
+		partial bool
+	}{
+		{"\x1b[2~x", KeyInsert, 4, false},
+		{"\x1b[24~", KeyF12, 5, false},
+		{"\x1b[2x", KeyF1, 3, false},
+		{"\x1b[2", KeyF1, 3, true},
+		{"\x1bO", 0, 0, true},
+		{"\x1bOB", 0, 0, false},
+		{"x", 0, 0, false},
+	}
+	for _, c := range cases {
+		k, n, partial := keys.match([]byte(c.in))
+		if (k == nil) != (c.key == 0) || (k != nil && k.key != c.key) || n != c.n || partial != c.partial {
+			t.Errorf("Bad match for %q: %v %d %v", c.in, k, n, partial)
+		}
+	}
+	b := []byte("\x1b[24~")
+	if allocs := testing.AllocsPerRun(100, func() { keys.match(b) }); allocs != 0 {
+		t.Errorf("Matching allocated %v times", allocs)
+	}
+}
+
+// pasteText returns a large block of text, with the odd function key.
+func pasteText() []byte {
+	var b bytes.Buffer
+	for b.Len() < 64*1024 {
+		b.WriteString("The quick brown fox jumps over the lazy dog.\r\x1b[A")
+	}
+	return b.Bytes()
+}
+
+func BenchmarkKeyMatch(b *testing.B) {
+	s, _ := mkTermScreen(b, "xterm-256color")
+	defer s.Fini()
+	ts := s.(*baseScreen).screenImpl.(*tScreen)
+	text := pasteText()
+
+	b.Run("Trie", func(b *testing.B) {
+		b.SetBytes(int64(len(text)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := range text {
+				ts.keys.match(text[j:])
+			}
+		}
+	})
+	// This is how keys were matched before the trie.
+	b.Run("Map", func(b *testing.B) {
+		b.SetBytes(int64(len(text)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := range text {
+				for e := range ts.keycodes {
+					esc := []byte(e)
+					if bytes.HasPrefix(text[j:], esc) {
+						break
+					}
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkPaste(b *testing.B) {
+	s, _ := mkTermScreen(b, "xterm-256color")
+	defer s.Fini()
+	ts := s.(*baseScreen).screenImpl.(*tScreen)
+	text := pasteText()
+
+	b.SetBytes(int64(len(text)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ts.collectEventsFromInput(bytes.NewBuffer(text), false)
+	}
+}
+
+func TestPasteText(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	s.EnablePaste()
+	s.SetPasteLimit(1024)
+	tty.Input("\x1b[200~one\rtwo\x1b[A")
+	tty.Input("\x1b[20")
+	tty.Input("1~x")
+	ev, ok := nextEvent(t, s).(*EventPaste)
+	if !ok || !ev.Whole() || ev.Start() || ev.End() {
+		t.Fatalf("Expected whole paste")
+	}
+	if ev.Text() != "one\ntwo\x1b[A" || ev.Truncated() {
+		t.Errorf("Bad paste: %q", ev.Text())
+	}
+	checkKey(t, nextEvent(t, s), KeyRune, 'x', ModNone, KeyEventPress)
+
+	s.SetPasteLimit(4)
+	tty.Input("\x1b[200~" + strings.Repeat("z", 1000) + "\x1b[201~")
+	ev, ok = nextEvent(t, s).(*EventPaste)
+	if !ok || ev.Text() != "zzzz" || !ev.Truncated() {
+		t.Fatalf("Expected truncated paste")
+	}
+
+	s.SetPasteLimit(0)
+	tty.Input("\x1b[200~y\x1b[201~")
+	if ev, ok := nextEvent(t, s).(*EventPaste); !ok || !ev.Start() || ev.End() {
+		t.Fatalf("Expected paste start")
+	}
+	checkKey(t, nextEvent(t, s), KeyRune, 'y', ModNone, KeyEventPress)
+	if ev, ok := nextEvent(t, s).(*EventPaste); !ok || ev.Start() || !ev.End() {
+		t.Fatalf("Expected paste end")
+	}
+
+	// a paste whose end never arrives is delivered after a while
+	s.SetPasteLimit(1024)
+	tty.Input("\x1b[200~ab")
+	time.Sleep(pasteTimeout + 100*time.Millisecond)
+	if ev, ok := nextEvent(t, s).(*EventPaste); !ok || ev.Text() != "ab" {
+		t.Fatalf("Expected abandoned paste")
+	}
+	tty.Input("c")
+	checkKey(t, nextEvent(t, s), KeyRune, 'c', ModNone, KeyEventPress)
+
+	// and turning paste off gives up on one in progress
+	tty.Input("\x1b[200~de")
+	ts := s.(*baseScreen).screenImpl.(*tScreen)
+	for i := 0; i < 100; i++ {
+		ts.Lock()
+		pasting := ts.pasting
+		ts.Unlock()
+		if pasting {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.DisablePaste()
+	tty.Input("f")
+	checkKey(t, nextEvent(t, s), KeyRune, 'f', ModNone, KeyEventPress)
+}
+
+func TestPasteSlow(t *testing.T) {
+	s, _ := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+	ts := s.(*baseScreen).screenImpl.(*tScreen)
+
+	// we feed the input ourselves, so keep the input timer out of it
+	ts.keytimer.Stop()
+
+	// the rest of a paste that was held up is still part of it
+	s.EnablePaste()
+	s.SetPasteLimit(1024)
+	evs := ts.collectEventsFromInput(bytes.NewBufferString("\x1b[200~ab"), false)
+	time.Sleep(pasteTimeout + 100*time.Millisecond)
+	evs = append(evs, ts.collectEventsFromInput(bytes.NewBufferString("cd\x1b[201~"), false)...)
+	if len(evs) != 1 {
+		t.Fatalf("Expected one event: %v", evs)
+	}
+	if ev, ok := evs[0].(*EventPaste); !ok || ev.Text() != "abcd" {
+		t.Errorf("Expected whole paste: %v", evs[0])
+	}
+}
+
+func TestMouseEncodings(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+	ts := s.(*baseScreen).screenImpl.(*tScreen)
+
+	waitOutput(t, tty, "\x1b[?1016$p\x1b[?1006$p\x1b[?1015$p\x1b[?1005$p")
+	s.EnableMouse()
+	waitOutput(t, tty, "\x1b[?1006h")
+
+	// cells of 10 by 20 pixels
+	tty.Lock()
+	tty.ws.PixelWidth, tty.ws.PixelHeight = 800, 480
+	cb := tty.cb
+	tty.Unlock()
+	cb()
+	for i := 0; ; i++ {
+		ts.Lock()
+		known := ts.cellW == 10 && ts.cellH == 20
+		ts.Unlock()
+		if known {
+			break
+		}
+		if i > 100 {
+			t.Fatalf("Cell size not found")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	tty.Input("\x1b[?1016;2$y\x1b[?1006;2$y\x1b[?1015;2$y\x1b[?1005;2$y")
+	waitOutput(t, tty, "\x1b[?1016h")
+	tty.Input("\x1b[<0;25;33M")
+	ev, ok := nextEvent(t, s).(*EventMouse)
+	if !ok || ev.Buttons() != Button1 {
+		t.Fatalf("Expected button press")
+	}
+	if x, y := ev.Position(); x != 2 || y != 1 {
+		t.Errorf("Bad position: %d, %d", x, y)
+	}
+	if x, y, ok := ev.PixelPosition(); !ok || x != 24 || y != 32 {
+		t.Errorf("Bad pixel position: %d, %d", x, y)
+	}
+
+	// urxvt
+	tty.Input("\x1b[?1016;0$y\x1b[?1006;0$y\x1b[?1015;2$y\x1b[?1005;2$y")
+	waitOutput(t, tty, "\x1b[?1016l\x1b[?1000h\x1b[?1002h\x1b[?1003h\x1b[?1015h")
+	tty.Input("\x1b[32;5;3M")
+	ev, ok = nextEvent(t, s).(*EventMouse)
+	if !ok || ev.Buttons() != Button1 {
+		t.Fatalf("Expected button press")
+	}
+	if x, y := ev.Position(); x != 4 || y != 2 {
+		t.Errorf("Bad position: %d, %d", x, y)
+	}
+	if _, _, ok := ev.PixelPosition(); ok {
+		t.Errorf("Pixel position reported")
+	}
+	// the button is offset by 32 too, so that motion is not a wheel
+	tty.Input("\x1b[64;6;3M")
+	if ev, ok = nextEvent(t, s).(*EventMouse); !ok || ev.Buttons() != Button1 {
+		t.Fatalf("Expected drag")
+	}
+
+	// UTF-8, where coordinates past 94 need two bytes
+	tty.Input("\x1b[?1016;0$y\x1b[?1006;0$y\x1b[?1015;0$y\x1b[?1005;2$y")
+	waitOutput(t, tty, "\x1b[?1005h")
+	tty.Input("\x1b[M " + string(rune(32+1+100)) + "$x")
+	ev, ok = nextEvent(t, s).(*EventMouse)
+	if !ok || ev.Buttons() != Button1 {
+		t.Fatalf("Expected button press")
+	}
+	if x, y := ev.Position(); x != 79 || y != 3 {
+		t.Errorf("Bad position: %d, %d", x, y)
+	}
+	checkKey(t, nextEvent(t, s), KeyRune, 'x', ModNone, KeyEventPress)
+	tty.Input("\x1b[M@&$")
+	ev, ok = nextEvent(t, s).(*EventMouse)
+	if !ok || ev.Buttons() != Button1 {
+		t.Fatalf("Expected drag")
+	}
+	if x, y := ev.Position(); x != 5 || y != 3 {
+		t.Errorf("Bad position: %d, %d", x, y)
+	}
+}
+
+func TestMouseShape(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+
+	s.SetMouseShape(MouseShapeResizeEW)
+	waitOutput(t, tty, "\x1b]22;ew-resize\x1b\\")
+	if err := s.Suspend(); err != nil {
+		t.Fatalf("Failed to suspend: %v", err)
+	}
+	waitOutput(t, tty, "\x1b]22;default\x1b\\")
+	if err := s.Resume(); err != nil {
+		t.Fatalf("Failed to resume: %v", err)
+	}
+	waitOutput(t, tty, "\x1b]22;ew-resize\x1b\\")
+}
+
+func TestMouseShapeUnsupported(t *testing.T) {
+	s, tty := mkTermScreen(t, "screen")
+	defer s.Fini()
+
+	s.SetMouseShape(MouseShapeResizeEW)
+	s.Show()
+	if out := tty.Output(); strings.Contains(out, "\x1b]22;") {
+		t.Errorf("Mouse shape sent: %q", out)
+	}
+}
+
+func TestScreenOptions(t *testing.T) {
+	ti, err := LookupTerminfo("xterm")
+	if err != nil {
+		t.Fatalf("Failed to find terminfo: %v", err)
+	}
+	tc := *ti
+	tty := newMockTty(80, 24)
+	s, err := NewTerminfoScreenWithOptions(tty, &tc,
+		WithAltScreen(false),
+		WithTrueColor(true),
+		WithPaste(),
+		WithEventQueueSize(3),
+		WithEscapeTimeout(300*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to get screen: %v", err)
+	}
+	if err = s.Init(); err != nil {
+		t.Fatalf("Failed to initialize screen: %v", err)
+	}
+	defer s.Fini()
+
+	waitOutput(t, tty, "\x1b[?2004h")
+	if out := tty.Output(); strings.Contains(out, "\x1b[?1049h") {
+		t.Errorf("Alternate screen used: %q", out)
+	}
+	if s.Colors() != 1<<24 {
+		t.Errorf("Expected true color: %d", s.Colors())
+	}
+	if tc.SetFgRGB != "" {
+		t.Errorf("Terminfo modified")
+	}
+	if size := s.EventQueueStats().Size; size != 3 {
+		t.Errorf("Bad queue size: %d", size)
+	}
+
+	if _, ok := s.PollEvent().(*EventResize); !ok {
+		t.Fatalf("Expected initial resize")
+	}
+	tty.Input("\x1b")
+	time.Sleep(100 * time.Millisecond)
+	if s.HasPendingEvent() {
+		t.Errorf("Escape timed out too soon")
+	}
+	checkKey(t, nextEvent(t, s), KeyEsc, 0, ModNone, KeyEventPress)
+}
+
+func TestAltScreenEnv(t *testing.T) {
+	ti, err := LookupTerminfo("xterm")
+	if err != nil {
+		t.Fatalf("Failed to find terminfo: %v", err)
+	}
+	tc := *ti
+	tty := newMockTty(80, 24)
+	s, err := NewTerminfoScreenWithOptions(tty, &tc)
+	if err != nil {
+		t.Fatalf("Failed to get screen: %v", err)
+	}
+	// without an option, the environment is checked when we start
+	t.Setenv("TCELL_ALTSCREEN", "disable")
+	if err = s.Init(); err != nil {
+		t.Fatalf("Failed to initialize screen: %v", err)
+	}
+	defer s.Fini()
+	if out := tty.Output(); strings.Contains(out, "\x1b[?1049h") {
+		t.Errorf("Alternate screen used: %q", out)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	s, tty := mkTermScreen(t, "xterm-256color")
+	defer s.Fini()
+	s.EnablePaste()
+	s.SetPasteLimit(100)
+	waitOutput(t, tty, "\x1b[?u\x1b[c")
+	tty.Input("\x1b[?0u\x1b[?62;22c")
+	waitOutput(t, tty, "\x1b[>1u")
+	s.SetKeyboardFlags(KeyRepeatEvents | KeyReleaseEvents)
+
+	ti, _ := LookupTerminfo("xterm-256color")
+	enc := NewEncoder(ti)
+
+	keys := []*EventKey{
+		NewEventKey(KeyRune, 'a', ModNone),
+		NewEventKey(KeyRune, 'é', ModNone),
+		NewEventKey(KeyRune, 'x', ModAlt),
+		NewEventKey(KeyCtrlA, 0, ModCtrl),
+		NewEventKey(KeyCtrlX, 0, ModCtrl|ModAlt),
+		NewEventKey(KeyEnter, 0, ModNone),
+		NewEventKey(KeyTab, 0, ModNone),
+		NewEventKey(KeyBacktab, 0, ModNone),
+		NewEventKey(KeyBackspace, 0, ModNone),
+		NewEventKey(KeyBackspace2, 0, ModNone),
+		NewEventKey(KeyF5, 0, ModNone),
+		NewEventKey(KeyF5, 0, ModShift),
+		NewEventKey(KeyUp, 0, ModCtrl),
+		NewEventKey(KeyLeft, 0, ModAlt),
+		NewEventKey(KeyHome, 0, ModNone),
+		NewEventKey(KeyDelete, 0, ModNone),
+		NewEventKey(KeyEsc, 0, ModNone),
+	}
+	roundTrip := func(keys []*EventKey) {
+		t.Helper()
+		for _, ev := range keys {
+			b := enc.Encode(ev)
+			if b == nil {
+				t.Fatalf("Failed to encode %s", ev.Name())
+			}
+			tty.Input(string(b))
+			checkKey(t, nextEvent(t, s), ev.Key(), ev.Rune(), ev.Modifiers(), ev.EventType())
+		}
+	}
+	roundTrip(keys)
+	if b := enc.Encode(newEventKeyType(KeyRune, 'a', ModNone, KeyEventRelease)); b != nil {
+		t.Errorf("Release encoded without kitty protocol: %q", b)
+	}
+
+	// the kitty protocol has just one backspace key
+	keys = slices.DeleteFunc(keys, func(ev *EventKey) bool { return ev.Key() == KeyBackspace2 })
+	enc.SetKittyKeyboard(true)
+	roundTrip(append(keys,
+		NewEventKey(KeyRune, 'a', ModSuper),
+		NewEventKey(KeyRune, 'A', ModShift|ModAlt),
+		NewEventKey(KeyF3, 0, ModAlt),
+		NewEventKey(KeyF13, 0, ModNone),
+		newEventKeyType(KeyRune, 'a', ModNone, KeyEventRelease),
+		newEventKeyType(KeyUp, 0, ModShift, KeyEventRepeat),
+		newEventKeyType(KeyDelete, 0, ModNone, KeyEventRelease)))
+	if b := string(enc.Encode(NewEventKey(KeyUp, 0, ModCtrl))); b != "\x1b[1;5A" {
+		t.Errorf("Bad kitty encoding: %q", b)
+	}
+	if b := string(enc.Encode(NewEventKey(KeyRune, 'A', ModShift))); b != "\x1b[97:65;2u" {
+		t.Errorf("Bad kitty encoding: %q", b)
+	}
+
+	mice := []*EventMouse{
+		NewEventMouse(3, 4, Button1, ModNone),
+		NewEventMouse(5, 4, Button1, ModNone),
+		NewEventMouse(5, 4, ButtonNone, ModNone),
+		NewEventMouse(6, 7, ButtonNone, ModNone),
+		NewEventMouse(79, 23, Button3, ModShift),
+		NewEventMouse(79, 23, ButtonNone, ModNone),
+		NewEventMouse(1, 2, WheelUp, ModCtrl),
+	}
+	for _, me := range []MouseEncoding{MouseEncodingSGR, MouseEncodingX10} {
+		enc.SetMouseEncoding(me)
+		for _, ev := range mice {
+			tty.Input(string(enc.Encode(ev)))
+			mev, ok := nextEvent(t, s).(*EventMouse)
+			if !ok {
+				t.Fatalf("Expected mouse event")
+			}
+			x, y := mev.Position()
+			ex, ey := ev.Position()
+			if x != ex || y != ey || mev.Buttons() != ev.Buttons() || mev.Modifiers() != ev.Modifiers() {
+				t.Errorf("Encoding %d: got %d,%d %x %x, expected %d,%d %x %x", me,
+					x, y, mev.Buttons(), mev.Modifiers(), ex, ey, ev.Buttons(), ev.Modifiers())
+			}
+		}
+	}
+	enc.SetMouseEncoding(MouseEncodingX10)
+	if b := string(enc.Encode(NewEventMouse(4, 2, Button1, ModNone))); b != "\x1b[M %#" {
+		t.Errorf("Bad X10 encoding: %q", b)
+	}
+	if b := string(enc.Encode(NewEventMouse(4, 2, ButtonNone, ModNone))); b != "\x1b[M#%#" {
+		t.Errorf("Bad X10 release encoding: %q", b)
+	}
+	enc.SetMouseEncoding(MouseEncodingURXVT)
+	if b := string(enc.Encode(NewEventMouse(4, 2, Button1, ModNone))); b != "\x1b[32;5;3M" {
+		t.Errorf("Bad urxvt encoding: %q", b)
+	}
+	if b := enc.Encode(NewEventResize(80, 24)); b != nil {
+		t.Errorf("Resize encoded: %q", b)
+	}
+
+	tty.Input(string(enc.Encode(NewEventPasteText("hello\nworld", 0))))
+	if ev, ok := nextEvent(t, s).(*EventPaste); !ok || ev.Text() != "hello\nworld" {
+		t.Errorf("Bad paste: %v", ev)
+	}
+	tty.Input(string(enc.Encode(NewEventFocus(false))))
+	if ev, ok := nextEvent(t, s).(*EventFocus); !ok || ev.Focused {
+		t.Errorf("Bad focus: %v", ev)
+	}
+}
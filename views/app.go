@@ -15,6 +15,7 @@
 package views
 
 import (
+	"context"
 	"sync"
 
 	"github.com/gdamore/tcell/v2"
@@ -28,8 +29,7 @@ type Application struct {
 	err      error
 	wg       sync.WaitGroup
 	paste    bool
-	stopQ    chan struct{}
-	eventQ   chan tcell.Event
+	cancel   context.CancelFunc
 	stopOnce sync.Once
 }
 
@@ -59,7 +59,7 @@ func (app *Application) initialize() error {
 // Quit causes the application to shutdown gracefully.  It does not wait
 // for the application to exit, but returns immediately.
 func (app *Application) Quit() {
-	app.stopOnce.Do(func() { close(app.stopQ) })
+	app.stopOnce.Do(func() { app.cancel() })
 }
 
 // Refresh causes the application forcibly redraw everything.  Use this
@@ -136,23 +136,13 @@ func (app *Application) run() {
 	screen.Clear()
 	widget.SetView(screen)
 
-	app.eventQ = make(chan tcell.Event, 16)
-	app.stopQ = make(chan struct{})
-	go screen.ChannelEvents(app.eventQ, app.stopQ)
+	var ctx context.Context
+	ctx, app.cancel = context.WithCancel(context.Background())
+	defer app.cancel()
 
-loop:
-	for {
-		if widget = app.widget; widget == nil {
-			break
-		}
-		widget.Draw()
-		screen.Show()
-
-		ev := <-app.eventQ
-		if ev == nil {
-			screen.Fini()
-			break loop
-		}
+	widget.Draw()
+	screen.Show()
+	for ev := range screen.Events(ctx) {
 		switch nev := ev.(type) {
 		case *eventAppFunc:
 			nev.fn()
@@ -162,6 +152,11 @@ loop:
 		default:
 			widget.HandleEvent(ev)
 		}
+		if widget = app.widget; widget == nil {
+			break
+		}
+		widget.Draw()
+		screen.Show()
 	}
 }
 
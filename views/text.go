@@ -15,7 +15,7 @@
 package views
 
 import (
-	"github.com/mattn/go-runewidth"
+	"slices"
 
 	"github.com/gdamore/tcell/v2"
 )
@@ -32,6 +32,7 @@ type Text struct {
 	lengths []int
 	width   int
 	height  int
+	policy  tcell.WidthPolicy
 
 	WidgetWatchers
 }
@@ -77,6 +78,7 @@ func (t *Text) Draw() {
 		return
 	}
 
+	t.remeasure()
 	width, height := v.Size()
 	if width == 0 || height == 0 {
 		return
@@ -155,9 +157,12 @@ func (t *Text) Alignment() Alignment {
 	return t.align
 }
 
-// SetView sets the View object used for the text bar.
+// SetView sets the View object used for the text bar.  If the view
+// has a width policy (see tcell.Screen.SetWidthPolicy), it is used to
+// determine the width of the text.
 func (t *Text) SetView(view View) {
 	t.view = view
+	t.measure()
 }
 
 // HandleEvent implements a tcell.EventHandler, but does nothing.
@@ -169,23 +174,32 @@ func (t *Text) HandleEvent(tcell.Event) bool {
 // styles on individual rune indices are reset, and the default style
 // for the widget is set.
 func (t *Text) SetText(s string) {
-	t.width = 0
 	t.text = []rune(s)
-	if len(t.widths) < len(t.text) {
-		t.widths = make([]int, len(t.text))
-	} else {
-		t.widths = t.widths[0:len(t.text)]
-	}
 	if len(t.styles) < len(t.text) {
 		t.styles = make([]tcell.Style, len(t.text))
 	} else {
 		t.styles = t.styles[0:len(t.text)]
 	}
+	for i := range t.styles {
+		t.styles[i] = t.style
+	}
+	t.measure()
+	t.PostEventWidgetContent(t)
+}
+
+// measure determines the widths of the runes, and of the lines.
+func (t *Text) measure() {
+	t.policy = viewWidthPolicy(t.view)
+	t.width = 0
+	if len(t.widths) < len(t.text) {
+		t.widths = make([]int, len(t.text))
+	} else {
+		t.widths = t.widths[0:len(t.text)]
+	}
 	t.lengths = []int{}
 	length := 0
 	for i, r := range t.text {
-		t.widths[i] = runewidth.RuneWidth(r)
-		t.styles[i] = t.style
+		t.widths[i] = t.policy.RuneWidth(r)
 		if r == '\n' {
 			t.lengths = append(t.lengths, length)
 			if length > t.width {
@@ -218,7 +232,36 @@ func (t *Text) SetText(s string) {
 		}
 	}
 	t.height = len(t.lengths)
-	t.PostEventWidgetContent(t)
+}
+
+// widthPolicy is implemented by views (and screens) that have a policy
+// for the width of characters.
+type widthPolicy interface {
+	GetWidthPolicy() tcell.WidthPolicy
+}
+
+// remeasure measures the text again if the width policy of the view has
+// changed (as it may, if the terminal was probed), returning true if so.
+func (t *Text) remeasure() bool {
+	if samePolicy(t.policy, viewWidthPolicy(t.view)) {
+		return false
+	}
+	t.measure()
+	return true
+}
+
+// viewWidthPolicy returns the width policy of the view, if it has one,
+// or else the default policy.
+func viewWidthPolicy(v View) tcell.WidthPolicy {
+	if wp, ok := v.(widthPolicy); ok {
+		return wp.GetWidthPolicy()
+	}
+	return tcell.DefaultWidthPolicy()
+}
+
+// samePolicy returns true if the policies give the same widths.
+func samePolicy(a, b tcell.WidthPolicy) bool {
+	return a.AmbiguousWide == b.AmbiguousWide && slices.Equal(a.Overrides, b.Overrides)
 }
 
 // Text returns the text that was set.
@@ -269,6 +312,7 @@ func (t *Text) StyleAt(pos int) tcell.Style {
 
 // Resize is called when our View changes sizes.
 func (t *Text) Resize() {
+	t.remeasure()
 	t.PostEventWidgetResize(t)
 }
 
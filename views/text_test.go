@@ -1,6 +1,10 @@
 package views
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
 
 func TestText(t *testing.T) {
 	text := &Text{}
@@ -17,3 +21,54 @@ Long
 		t.Errorf("Incorrect width: %d, expected: %d", text.width, 20)
 	}
 }
+
+func TestTextWidthPolicy(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatalf("Failed to initialize screen: %v", err)
+	}
+	defer s.Fini()
+	s.SetWidthPolicy(tcell.WidthPolicy{AmbiguousWide: true})
+
+	text := &Text{}
+	text.SetText("\u2500\u2500\u2500")
+	if text.width != 3 {
+		t.Errorf("Incorrect width: %d, expected: %d", text.width, 3)
+	}
+	text.SetView(NewViewPort(s, 0, 0, 10, 1))
+	if text.width != 6 {
+		t.Errorf("Incorrect width: %d, expected: %d", text.width, 6)
+	}
+	text.Draw()
+	if _, _, _, width := s.GetContent(2, 0); width != 2 {
+		t.Errorf("Incorrect cell width: %d, expected: %d", width, 2)
+	}
+
+	// a change of policy is noticed when drawing
+	s.SetWidthPolicy(tcell.WidthPolicy{})
+	text.Draw()
+	if text.width != 3 {
+		t.Errorf("Incorrect width: %d, expected: %d", text.width, 3)
+	}
+}
+
+func TestTextBarWidthPolicy(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatalf("Failed to initialize screen: %v", err)
+	}
+	defer s.Fini()
+	s.SetSize(10, 1)
+	s.SetWidthPolicy(tcell.WidthPolicy{AmbiguousWide: true})
+
+	bar := NewTextBar()
+	bar.SetRight("\u2500\u2500", tcell.StyleDefault)
+	bar.SetView(s)
+	if w, _ := bar.Size(); w != 4 {
+		t.Errorf("Incorrect width: %d, expected: %d", w, 4)
+	}
+	bar.Draw()
+	if r, _, _, width := s.GetContent(6, 0); r != '\u2500' || width != 2 {
+		t.Errorf("Incorrect cell: %q %d", r, width)
+	}
+}
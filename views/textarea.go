@@ -34,6 +34,9 @@ type TextArea struct {
 
 type linesModel struct {
 	runes  [][]rune
+	cells  [][]lineCell
+	view   View
+	policy tcell.WidthPolicy
 	width  int
 	height int
 	x      int
@@ -43,12 +46,58 @@ type linesModel struct {
 	style  tcell.Style
 }
 
+// lineCell is the content of one column of a line.  The columns covered
+// by the right side of a wide character have a width of zero.
+type lineCell struct {
+	ch    rune
+	comb  []rune
+	width int
+}
+
 func (m *linesModel) GetCell(x, y int) (rune, tcell.Style, []rune, int) {
-	if x < 0 || y < 0 || y >= m.height || x >= len(m.runes[y]) {
+	if x < 0 || y < 0 || y >= m.height || x >= len(m.cells[y]) || m.cells[y][x].width == 0 {
 		return 0, m.style, nil, 1
 	}
-	// XXX: extend this to support combining and full width chars
-	return m.runes[y][x], m.style, nil, 1
+	c := &m.cells[y][x]
+	return c.ch, m.style, c.comb, c.width
+}
+
+// measure lays out the lines in columns, using the width policy of the
+// view.
+func (m *linesModel) measure() {
+	m.policy = viewWidthPolicy(m.view)
+	m.width = 0
+	m.cells = make([][]lineCell, len(m.runes))
+	for row, line := range m.runes {
+		var cells []lineCell
+		base := -1
+		for _, r := range line {
+			w := m.policy.RuneWidth(r)
+			if w == 0 && base >= 0 {
+				cells[base].comb = append(cells[base].comb, r)
+				continue
+			}
+			base = len(cells)
+			cells = append(cells, lineCell{ch: r, width: max(w, 1)})
+			for i := 1; i < w; i++ {
+				cells = append(cells, lineCell{})
+			}
+		}
+		m.cells[row] = cells
+		if len(cells) > m.width {
+			m.width = len(cells)
+		}
+	}
+}
+
+// remeasure lays out the lines again if the width policy of the view
+// has changed, returning true if so.
+func (m *linesModel) remeasure() bool {
+	if samePolicy(m.policy, viewWidthPolicy(m.view)) {
+		return false
+	}
+	m.measure()
+	return true
 }
 
 func (m *linesModel) GetBounds() (int, int) {
@@ -90,7 +139,6 @@ func (m *linesModel) GetCursor() (int, int, bool, bool) {
 func (ta *TextArea) SetLines(lines []string) {
 	ta.Init()
 	m := ta.model
-	m.width = 0
 
 	// extend slice before using m.runes[row] to avoid panic
 	slice := make([][]rune, len(lines))
@@ -100,12 +148,10 @@ func (ta *TextArea) SetLines(lines []string) {
 		for _, ch := range line {
 			m.runes[row] = append(m.runes[row], ch)
 		}
-		if len(m.runes[row]) > m.width {
-			m.width = len(m.runes[row])
-		}
 	}
 
 	m.height = len(m.runes)
+	m.measure()
 
 	ta.CellView.SetModel(m)
 }
@@ -137,6 +183,37 @@ func (ta *TextArea) SetContent(text string) {
 	ta.SetLines(lines)
 }
 
+// SetView sets the View context.  The width policy of the view, if it
+// has one, determines the width of the characters.
+func (ta *TextArea) SetView(view View) {
+	ta.Init()
+	ta.model.view = view
+	ta.model.measure()
+	ta.CellView.SetView(view)
+}
+
+// Draw draws the content.
+func (ta *TextArea) Draw() {
+	ta.Init()
+	ta.remeasure()
+	ta.CellView.Draw()
+}
+
+// Resize is called when the View is resized.
+func (ta *TextArea) Resize() {
+	ta.Init()
+	ta.remeasure()
+	ta.CellView.Resize()
+}
+
+// remeasure lays out the text again if the width policy has changed,
+// so that the content size matches.
+func (ta *TextArea) remeasure() {
+	if ta.model.remeasure() {
+		ta.port.SetContentSize(ta.model.width, ta.model.height, true)
+	}
+}
+
 // Init initializes the TextArea.
 func (ta *TextArea) Init() {
 	ta.once.Do(func() {
@@ -1,6 +1,10 @@
 package views
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
 
 func TestSetContent(t *testing.T) {
 	ta := &TextArea{}
@@ -15,3 +19,35 @@ func TestSetContent(t *testing.T) {
 		t.Errorf("Incorrect width: %d, expected: %d", ta.model.width, 11)
 	}
 }
+
+func TestTextAreaWidths(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatalf("Failed to initialize screen: %v", err)
+	}
+	defer s.Fini()
+	s.SetSize(10, 2)
+
+	ta := NewTextArea()
+	ta.SetContent("a\u4e16b\ne\u0301\u2500")
+	ta.SetView(s)
+	if ta.model.width != 4 {
+		t.Errorf("Incorrect width: %d, expected: %d", ta.model.width, 4)
+	}
+	ta.Draw()
+	if r, _, _, width := s.GetContent(1, 0); r != '\u4e16' || width != 2 {
+		t.Errorf("Incorrect wide cell: %q %d", r, width)
+	}
+	if r, _, _, _ := s.GetContent(3, 0); r != 'b' {
+		t.Errorf("Incorrect cell after wide: %q", r)
+	}
+	if r, comb, _, _ := s.GetContent(0, 1); r != 'e' || len(comb) != 1 {
+		t.Errorf("Incorrect combining cell: %q %q", r, comb)
+	}
+
+	s.SetWidthPolicy(tcell.WidthPolicy{AmbiguousWide: true})
+	ta.Draw()
+	if ta.model.width != 4 || len(ta.model.cells[1]) != 3 {
+		t.Errorf("Not measured again: %d %d", ta.model.width, len(ta.model.cells[1]))
+	}
+}
@@ -113,13 +113,25 @@ func (t *TextBar) SetView(view View) {
 	t.lview.SetView(view)
 	t.rview.SetView(view)
 	t.cview.SetView(view)
+	t.remeasure()
 	t.changed = true
 }
 
+// remeasure measures the text again if the width policy of the view
+// has changed, and notes that the layout must be redone if so.
+func (t *TextBar) remeasure() {
+	for _, text := range []*Text{&t.left, &t.center, &t.right} {
+		if text.remeasure() {
+			t.changed = true
+		}
+	}
+}
+
 // Draw draws the TextBar into its View context.
 func (t *TextBar) Draw() {
 
 	t.initialize()
+	t.remeasure()
 	if t.changed {
 		t.layout()
 	}
@@ -141,6 +153,7 @@ func (t *TextBar) Draw() {
 // updates the layout.
 func (t *TextBar) Resize() {
 	t.initialize()
+	t.remeasure()
 	t.layout()
 
 	t.left.Resize()
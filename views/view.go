@@ -281,6 +281,15 @@ func (v *ViewPort) SetView(view View) {
 	v.v = view
 }
 
+// GetWidthPolicy returns the width policy of the parent View, so that
+// content drawn in the ViewPort can be measured the same way.
+func (v *ViewPort) GetWidthPolicy() tcell.WidthPolicy {
+	if wp, ok := v.v.(widthPolicy); ok {
+		return wp.GetWidthPolicy()
+	}
+	return tcell.DefaultWidthPolicy()
+}
+
 // NewViewPort returns a new ViewPort (and hence also a View).
 // The x and y coordinates are an offset relative to the parent.
 // The origin 0,0 represents the upper left.  The width and height
@@ -217,6 +217,10 @@ function beep() {
   beepAudio.play();
 }
 
+function setMouseShape(shape) {
+  term.style.cursor = shape;
+}
+
 function setTitle(title) {
   document.title = title;
 }
@@ -0,0 +1,96 @@
+// Copyright 2025 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	runewidth "github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
+
+// WidthPolicy determines how many cells characters are assumed to occupy.
+// Most characters have a well defined width, but some, described by Unicode
+// as East Asian "ambiguous" width (such as box drawing characters, Greek
+// letters, and various symbols), are displayed as two cells wide by many
+// terminals in CJK locales, and as one cell wide by others.  If the
+// application and the terminal disagree about this, the display is garbled.
+type WidthPolicy struct {
+	// AmbiguousWide makes ambiguous width characters two cells wide.
+	AmbiguousWide bool
+
+	// Overrides sets the width of ranges of characters, taking
+	// precedence over everything else.  If ranges overlap, the
+	// later one wins.
+	Overrides []WidthRange
+}
+
+// WidthRange is a range of characters, from First to Last inclusive,
+// that are Width cells wide.
+type WidthRange struct {
+	First rune
+	Last  rune
+	Width int
+}
+
+// DefaultWidthPolicy returns the policy used unless another is set.
+// Ambiguous width characters are narrow, unless the RUNEWIDTH_EASTASIAN
+// environment variable says otherwise.
+func DefaultWidthPolicy() WidthPolicy {
+	return WidthPolicy{AmbiguousWide: runewidth.DefaultCondition.EastAsianWidth}
+}
+
+// RuneWidth returns the width of the rune, in cells.
+func (wp WidthPolicy) RuneWidth(r rune) int {
+	for i := len(wp.Overrides) - 1; i >= 0; i-- {
+		if o := &wp.Overrides[i]; r >= o.First && r <= o.Last {
+			return o.Width
+		}
+	}
+	if wp.AmbiguousWide == runewidth.DefaultCondition.EastAsianWidth {
+		return runewidth.RuneWidth(r)
+	}
+	c := runewidth.Condition{
+		EastAsianWidth:     wp.AmbiguousWide,
+		StrictEmojiNeutral: runewidth.DefaultCondition.StrictEmojiNeutral,
+	}
+	return c.RuneWidth(r)
+}
+
+// StringWidth returns the width of the string, in cells.
+func (wp WidthPolicy) StringWidth(s string) int {
+	width := 0
+	for s != "" {
+		var cluster string
+		cluster, s, _, _ = uniseg.FirstGraphemeClusterInString(s, -1)
+		runes := []rune(cluster)
+		width += wp.clusterWidth(runes[0], runes[1:])
+	}
+	return width
+}
+
+// clusterWidth returns the display width of a grapheme cluster.  The width
+// of a cluster is mostly that of its first rune, but some sequences (such
+// as regional indicator pairs, or emoji presentation selectors) change it.
+func (wp WidthPolicy) clusterWidth(mainc rune, combc []rune) int {
+	if len(combc) == 0 {
+		return wp.RuneWidth(mainc)
+	}
+	_, _, width, _ := uniseg.FirstGraphemeClusterInString(string(mainc)+string(combc), -1)
+	if width == 1 {
+		// combining marks don't change the width, which may be
+		// ambiguous, or overridden
+		return wp.RuneWidth(mainc)
+	}
+	return width
+}
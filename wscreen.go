@@ -18,19 +18,33 @@
 package tcell
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"image"
 	"strings"
 	"sync"
 	"syscall/js"
+	"time"
 	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2/terminfo"
 )
 
 func NewTerminfoScreen() (Screen, error) {
-	t := &wScreen{}
+	return newTerminfoScreen(nil)
+}
+
+func newTerminfoScreen(opts []ScreenOption) (Screen, error) {
+	o := newScreenOptions(opts)
+	if o.inline > 0 {
+		return nil, ErrNotSupported
+	}
+	t := &wScreen{evch: newEventQueue(o.queueSize)}
 	t.fallback = make(map[rune]string)
+	t.mouseFlags = o.mouse
+	t.pasteEnabled = o.paste
+	t.focusEnabled = o.focus
 
 	return &baseScreen{screenImpl: t}, nil
 }
@@ -44,12 +58,18 @@ type wScreen struct {
 	clear        bool
 	flagsPresent bool
 	pasteEnabled bool
+	focusEnabled bool
+	pasteLimit   int
+	pasting      bool
+	pasteText    strings.Builder
 	mouseFlags   MouseFlags
+	clicks       mouseTracker
+	kbdFlags     KeyboardFlags
 
 	cursorStyle CursorStyle
 
 	quit     chan struct{}
-	evch     chan Event
+	evch     *eventQueue
 	fallback map[rune]string
 	finiOnce sync.Once
 
@@ -58,7 +78,6 @@ type wScreen struct {
 
 func (t *wScreen) Init() error {
 	t.w, t.h = 80, 24 // default for html as of now
-	t.evch = make(chan Event, 10)
 	t.quit = make(chan struct{})
 
 	t.Lock()
@@ -72,11 +91,20 @@ func (t *wScreen) Init() error {
 	js.Global().Set("onMouseMove", js.FuncOf(t.unset))
 	js.Global().Set("onFocus", js.FuncOf(t.unset))
 
+	t.Lock()
+	t.enableMouse(t.mouseFlags)
+	t.enablePasting(t.pasteEnabled)
+	if t.focusEnabled {
+		js.Global().Set("onFocus", js.FuncOf(t.onFocus))
+	}
+	t.Unlock()
+
 	return nil
 }
 
 func (t *wScreen) Fini() {
 	t.finiOnce.Do(func() {
+		t.SetMouseShape(MouseShapeDefault)
 		close(t.quit)
 	})
 }
@@ -236,6 +264,10 @@ func (t *wScreen) enableMouse(f MouseFlags) {
 	}
 }
 
+func (t *wScreen) SetMouseClickInterval(interval time.Duration) {
+	t.clicks.setInterval(interval)
+}
+
 func (t *wScreen) DisableMouse() {
 	t.Lock()
 	t.mouseFlags = 0
@@ -254,6 +286,16 @@ func (t *wScreen) DisablePaste() {
 	t.Lock()
 	t.pasteEnabled = false
 	t.enablePasting(false)
+	t.pasting = false
+	t.Unlock()
+}
+
+func (t *wScreen) SetPasteLimit(limit int) {
+	t.Lock()
+	t.pasteLimit = limit
+	if limit <= 0 {
+		t.pasting = false
+	}
 	t.Unlock()
 }
 
@@ -267,22 +309,98 @@ func (t *wScreen) enablePasting(on bool) {
 
 func (t *wScreen) EnableFocus() {
 	t.Lock()
+	t.focusEnabled = true
 	js.Global().Set("onFocus", js.FuncOf(t.onFocus))
 	t.Unlock()
 }
 
 func (t *wScreen) DisableFocus() {
 	t.Lock()
+	t.focusEnabled = false
 	js.Global().Set("onFocus", js.FuncOf(t.unset))
 	t.Unlock()
 }
 
+// SetKeyboardFlags records the requested flags, but the browser
+// only reports key presses to us, so it has no other effect.
+func (t *wScreen) SetKeyboardFlags(flags KeyboardFlags) {
+	t.Lock()
+	t.kbdFlags = flags
+	t.Unlock()
+}
+
 func (s *wScreen) GetClipboard() {
 }
 
+func (s *wScreen) GetClipboardContext(context.Context) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
 func (s *wScreen) SetClipboard(_ []byte) {
 }
 
+func (s *wScreen) PrintAbove(...string) {
+}
+
+func (t *wScreen) DrawImage(int, int, int, int, image.Image) bool {
+	return false
+}
+
+func (t *wScreen) LoadImage(image.Image) Image {
+	return nil
+}
+
+func (t *wScreen) GetColorScheme() {
+}
+
+func (t *wScreen) EnableColorScheme() {
+}
+
+func (t *wScreen) DisableColorScheme() {
+}
+
+func (t *wScreen) SetPaletteColor(int, Color) {
+}
+
+func (t *wScreen) ResetPalette() {
+}
+
+func (t *wScreen) QueryPaletteColor(int) {
+}
+
+func (t *wScreen) QueryCursorPosition() {
+}
+
+func (t *wScreen) GetCursorPosition(time.Duration) (int, int, bool) {
+	return 0, 0, false
+}
+
+func (t *wScreen) Query(context.Context, string, ReplyMatcher) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+func (t *wScreen) PostQuery(string, ReplyMatcher) {
+}
+
+func (t *wScreen) Capabilities() Capabilities {
+	return Capabilities{
+		TrueColor:      true,
+		Focus:          true,
+		BracketedPaste: true,
+		Name:           "browser",
+	}
+}
+
+func (t *wScreen) ProbeCapabilities(time.Duration) Capabilities {
+	return t.Capabilities()
+}
+
+func (t *wScreen) ProbeWidths(time.Duration) WidthPolicy {
+	t.Lock()
+	defer t.Unlock()
+	return t.cells.GetWidthPolicy()
+}
+
 func (t *wScreen) Size() (int, int) {
 	t.Lock()
 	w, h := t.w, t.h
@@ -316,10 +434,7 @@ func (t *wScreen) clip(x, y int) (int, int) {
 }
 
 func (t *wScreen) postEvent(ev Event) {
-	select {
-	case t.evch <- ev:
-	case <-t.quit:
-	}
+	_ = t.evch.post(ev, t.quit)
 }
 
 func (t *wScreen) onMouseEvent(this js.Value, args []js.Value) interface{} {
@@ -353,7 +468,9 @@ func (t *wScreen) onMouseEvent(this js.Value, args []js.Value) interface{} {
 		mod |= ModCtrl
 	}
 
-	t.postEvent(NewEventMouse(args[0].Int(), args[1].Int(), button, mod))
+	ev := NewEventMouse(args[0].Int(), args[1].Int(), button, mod)
+	t.clicks.track(ev)
+	t.postEvent(ev)
 	return nil
 }
 
@@ -392,21 +509,66 @@ func (t *wScreen) onKeyEvent(this js.Value, args []js.Value) interface{} {
 
 	// next try function keys
 	if k, ok := WebKeyNames[key]; ok {
-		t.postEvent(NewEventKey(k, 0, mod))
+		if !t.addPaste(k, 0) {
+			t.postEvent(NewEventKey(k, 0, mod))
+		}
 		return nil
 	}
 
 	// finally try normal, printable chars
 	r, _ := utf8.DecodeRuneInString(key)
-	t.postEvent(NewEventKey(KeyRune, r, mod))
+	if !t.addPaste(KeyRune, r) {
+		t.postEvent(NewEventKey(KeyRune, r, mod))
+	}
 	return nil
 }
 
 func (t *wScreen) onPaste(this js.Value, args []js.Value) interface{} {
-	t.postEvent(NewEventPaste(args[0].Bool()))
+	start := args[0].Bool()
+	t.Lock()
+	if t.pasteLimit > 0 {
+		// collect the keys of the paste, and deliver them at the end
+		if !start && t.pasting {
+			ev := NewEventPasteText(t.pasteText.String(), t.pasteLimit)
+			t.pasting = false
+			t.Unlock()
+			t.postEvent(ev)
+			return nil
+		}
+		if start {
+			t.pasting = true
+			t.pasteText.Reset()
+			t.Unlock()
+			return nil
+		}
+	}
+	t.Unlock()
+	t.postEvent(NewEventPaste(start))
 	return nil
 }
 
+// addPaste adds a key to the paste being collected, if any.
+func (t *wScreen) addPaste(key Key, r rune) bool {
+	t.Lock()
+	defer t.Unlock()
+	if !t.pasting {
+		return false
+	}
+	switch key {
+	case KeyEnter:
+		r = '\n'
+	case KeyTab:
+		r = '\t'
+	case KeyRune:
+	default:
+		return true
+	}
+	if t.pasteText.Len() <= t.pasteLimit {
+		t.pasteText.WriteRune(r)
+	}
+	return true
+}
+
 func (t *wScreen) onFocus(this js.Value, args []js.Value) interface{} {
 	t.postEvent(NewEventFocus(args[0].Bool()))
 	return nil
@@ -526,7 +688,7 @@ func (t *wScreen) GetCells() *CellBuffer {
 	return &t.cells
 }
 
-func (t *wScreen) EventQ() chan Event {
+func (t *wScreen) EventQ() *eventQueue {
 	return t.evch
 }
 
@@ -538,6 +700,12 @@ func (t *wScreen) SetTitle(title string) {
 	js.Global().Call("setTitle", title)
 }
 
+func (t *wScreen) SetMouseShape(shape MouseShape) {
+	if name, ok := mouseShapeNames[shape]; ok {
+		js.Global().Call("setMouseShape", name)
+	}
+}
+
 // WebKeyNames maps string names reported from HTML
 // (KeyboardEvent.key) to tcell accepted keys.
 var WebKeyNames = map[string]Key{